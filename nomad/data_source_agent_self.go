@@ -0,0 +1,238 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceAgentSelf() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAgentSelfRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Description: "Name of the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"region": {
+				Description: "Region of the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"datacenter": {
+				Description: "Datacenter of the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"version": {
+				Description: "Version of Nomad run by the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"revision": {
+				Description: "Git revision of the Nomad build run by the agent.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"server": {
+				Description: "Whether the agent runs in server mode.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+			"client": {
+				Description: "Whether the agent runs in client mode.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+			"acl_enabled": {
+				Description: "Whether ACLs are enabled on the agent.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+			"bind_addr": {
+				Computed: true,
+				Type:     schema.TypeString,
+			},
+			"data_dir": {
+				Computed: true,
+				Type:     schema.TypeString,
+			},
+			"log_level": {
+				Computed: true,
+				Type:     schema.TypeString,
+			},
+			"member": {
+				Description: "The gossip membership of the agent, only set for servers.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: agentMemberSchema(),
+				},
+			},
+			"stats": {
+				Description: "Stats of the agent, keyed by section and name, e.g. `nomad.leader`.",
+				Computed:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// agentMemberSchema returns the attributes of a gossip member shared by the
+// nomad_agent_self and nomad_server_members data sources.
+func agentMemberSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+		"address": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+		"port": {
+			Computed: true,
+			Type:     schema.TypeInt,
+		},
+		"status": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+		"region": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+		"datacenter": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+		"build": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+		"raft_version": {
+			Computed: true,
+			Type:     schema.TypeString,
+		},
+		"tags": {
+			Computed: true,
+			Type:     schema.TypeMap,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func dataSourceAgentSelfRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	log.Printf("[DEBUG] Reading agent self")
+	self, err := client.Agent().Self()
+	if err != nil {
+		return fmt.Errorf("error reading agent self: %v", err)
+	}
+	log.Printf("[DEBUG] Read agent self")
+
+	cfg := self.Config
+	name := agentConfigString(cfg, "NodeName")
+	if name == "" {
+		name = self.Member.Name
+	}
+
+	var member []interface{}
+	if self.Member.Name != "" {
+		member = []interface{}{agentMemberRaw(&self.Member)}
+	}
+
+	d.SetId(client.Address() + "/agent/self")
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("name", name)
+	sw.Set("region", agentConfigString(cfg, "Region"))
+	sw.Set("datacenter", agentConfigString(cfg, "Datacenter"))
+	sw.Set("version", agentVersion(cfg))
+	sw.Set("revision", agentConfigString(cfg, "Version", "Revision"))
+	sw.Set("server", agentConfigBool(cfg, "Server", "Enabled"))
+	sw.Set("client", agentConfigBool(cfg, "Client", "Enabled"))
+	sw.Set("acl_enabled", agentConfigBool(cfg, "ACL", "Enabled"))
+	sw.Set("bind_addr", agentConfigString(cfg, "BindAddr"))
+	sw.Set("data_dir", agentConfigString(cfg, "DataDir"))
+	sw.Set("log_level", agentConfigString(cfg, "LogLevel"))
+	sw.Set("member", member)
+	sw.Set("stats", agentStatsRaw(self.Stats))
+	return sw.Error()
+}
+
+// agentConfigValue walks the decoded agent configuration along path and
+// returns the value found, or nil if any element is missing.
+func agentConfigValue(cfg map[string]interface{}, path ...string) interface{} {
+	var v interface{} = cfg
+	for _, p := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[p]
+	}
+	return v
+}
+
+func agentConfigString(cfg map[string]interface{}, path ...string) string {
+	s, _ := agentConfigValue(cfg, path...).(string)
+	return s
+}
+
+func agentConfigBool(cfg map[string]interface{}, path ...string) bool {
+	b, _ := agentConfigValue(cfg, path...).(bool)
+	return b
+}
+
+// agentVersion formats the version of the agent the same way `nomad version`
+// does, including its pre-release and metadata suffixes.
+func agentVersion(cfg map[string]interface{}) string {
+	version := agentConfigString(cfg, "Version", "Version")
+	if version == "" {
+		return ""
+	}
+	if pre := agentConfigString(cfg, "Version", "VersionPrerelease"); pre != "" {
+		version += "-" + pre
+	}
+	if md := agentConfigString(cfg, "Version", "VersionMetadata"); md != "" {
+		version += "+" + md
+	}
+	return version
+}
+
+func agentStatsRaw(stats map[string]map[string]string) map[string]interface{} {
+	ret := make(map[string]interface{})
+	for section, values := range stats {
+		for k, v := range values {
+			ret[section+"."+k] = v
+		}
+	}
+	return ret
+}
+
+func agentMemberRaw(m *api.AgentMember) map[string]interface{} {
+	tags := make(map[string]interface{}, len(m.Tags))
+	for k, v := range m.Tags {
+		tags[k] = v
+	}
+
+	return map[string]interface{}{
+		"name":         m.Name,
+		"address":      m.Addr,
+		"port":         int(m.Port),
+		"status":       m.Status,
+		"region":       m.Tags["region"],
+		"datacenter":   m.Tags["dc"],
+		"build":        m.Tags["build"],
+		"raft_version": m.Tags["raft_vsn"],
+		"tags":         tags,
+	}
+}
@@ -0,0 +1,122 @@
+package nomad
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceAgentSelf_basic(t *testing.T) {
+	dataSourceName := "data.nomad_agent_self.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `data "nomad_agent_self" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "region", "global"),
+					resource.TestCheckResourceAttr(dataSourceName, "datacenter", "dc1"),
+					resource.TestCheckResourceAttr(dataSourceName, "server", "true"),
+					resource.TestMatchResourceAttr(dataSourceName, "version", regexp.MustCompile(`^\d+\.\d+\.\d+`)),
+					resource.TestCheckResourceAttrSet(dataSourceName, "name"),
+					resource.TestCheckResourceAttr(dataSourceName, "member.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "member.0.status", "alive"),
+					resource.TestCheckResourceAttr(dataSourceName, "member.0.region", "global"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "stats.nomad.leader"),
+				),
+			},
+		},
+	})
+}
+
+func TestAgentVersion(t *testing.T) {
+	cases := []struct {
+		name    string
+		version map[string]interface{}
+		want    string
+	}{
+		{
+			name:    "release",
+			version: map[string]interface{}{"Version": "1.1.0"},
+			want:    "1.1.0",
+		},
+		{
+			name: "prerelease",
+			version: map[string]interface{}{
+				"Version":           "1.1.0",
+				"VersionPrerelease": "beta1",
+				"VersionMetadata":   "ent",
+			},
+			want: "1.1.0-beta1+ent",
+		},
+		{
+			name: "missing",
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := map[string]interface{}{}
+			if tc.version != nil {
+				cfg["Version"] = tc.version
+			}
+			require.Equal(t, tc.want, agentVersion(cfg))
+		})
+	}
+}
+
+func TestAgentConfigValue(t *testing.T) {
+	cfg := map[string]interface{}{
+		"Region": "global",
+		"Server": map[string]interface{}{"Enabled": true},
+	}
+
+	require.Equal(t, "global", agentConfigString(cfg, "Region"))
+	require.True(t, agentConfigBool(cfg, "Server", "Enabled"))
+	require.False(t, agentConfigBool(cfg, "Client", "Enabled"))
+	require.Equal(t, "", agentConfigString(cfg, "Region", "Name"))
+}
+
+func TestAgentStatsRaw(t *testing.T) {
+	stats := map[string]map[string]string{
+		"nomad": {"leader": "true"},
+		"raft":  {"state": "Leader"},
+	}
+	require.Equal(t, map[string]interface{}{
+		"nomad.leader": "true",
+		"raft.state":   "Leader",
+	}, agentStatsRaw(stats))
+}
+
+func TestAgentMemberRaw(t *testing.T) {
+	m := &api.AgentMember{
+		Name:   "server-1.global",
+		Addr:   "10.0.0.1",
+		Port:   4648,
+		Status: "alive",
+		Tags: map[string]string{
+			"region":   "global",
+			"dc":       "dc1",
+			"build":    "1.1.0",
+			"raft_vsn": "3",
+			"port":     "4647",
+		},
+	}
+
+	raw := agentMemberRaw(m)
+	require.Equal(t, "server-1.global", raw["name"])
+	require.Equal(t, "10.0.0.1", raw["address"])
+	require.Equal(t, 4648, raw["port"])
+	require.Equal(t, "alive", raw["status"])
+	require.Equal(t, "global", raw["region"])
+	require.Equal(t, "dc1", raw["datacenter"])
+	require.Equal(t, "1.1.0", raw["build"])
+	require.Equal(t, "3", raw["raft_version"])
+	require.Equal(t, "4647", raw["tags"].(map[string]interface{})["port"])
+}
@@ -0,0 +1,317 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceAllocation() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAllocationRead,
+
+		Schema: map[string]*schema.Schema{
+			"allocation_id": {
+				Description: "ID of the allocation, or a prefix that matches a single allocation.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"namespace": {
+				Description: "Namespace of the allocation. Use `*` to search all namespaces.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"eval_id":             computedString(),
+			"name":                computedString(),
+			"node_id":             computedString(),
+			"node_name":           computedString(),
+			"job_id":              computedString(),
+			"task_group":          computedString(),
+			"client_status":       computedString(),
+			"client_description":  computedString(),
+			"desired_status":      computedString(),
+			"desired_description": computedString(),
+			"deployment_id":       computedString(),
+			"followup_eval_id":    computedString(),
+			"previous_allocation": computedString(),
+			"next_allocation":     computedString(),
+			"create_time":         computedString(),
+			"modify_time":         computedString(),
+			"address": {
+				Description: "IP address of the allocation network.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"job_version": {
+				Computed: true,
+				Type:     schema.TypeInt,
+			},
+			"ports": allocatedPortsSchema(),
+			"deployment_status": {
+				Description: "Status of the allocation in its deployment.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"healthy": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"health_determined": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"canary": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"timestamp": computedString(),
+					},
+				},
+			},
+			"reschedule_events": {
+				Description: "Previous attempts at rescheduling the allocation.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"prev_alloc_id":   computedString(),
+						"prev_node_id":    computedString(),
+						"reschedule_time": computedString(),
+					},
+				},
+			},
+			"task_states": {
+				Description: "State of each task of the allocation.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name":  computedString(),
+						"state": computedString(),
+						"failed": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"restarts": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"last_restart": computedString(),
+						"started_at":   computedString(),
+						"finished_at":  computedString(),
+						"events": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"type":    computedString(),
+									"time":    computedString(),
+									"message": computedString(),
+									"details": {
+										Computed: true,
+										Type:     schema.TypeMap,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAllocationRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	allocID := d.Get("allocation_id").(string)
+	qOpts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+
+	// Resolve prefixes to a single allocation ID.
+	if len(allocID) < 36 {
+		stubs, _, err := client.Allocations().List(&api.QueryOptions{
+			Namespace: qOpts.Namespace,
+			Prefix:    allocID,
+		})
+		if err != nil {
+			return fmt.Errorf("error listing allocations: %v", err)
+		}
+		switch len(stubs) {
+		case 0:
+			return fmt.Errorf("no allocation found with prefix %q", allocID)
+		case 1:
+			allocID = stubs[0].ID
+			qOpts.Namespace = stubs[0].Namespace
+		default:
+			return fmt.Errorf("prefix %q matches %d allocations, use a longer prefix", allocID, len(stubs))
+		}
+	}
+
+	log.Printf("[DEBUG] reading allocation %q", allocID)
+	alloc, _, err := client.Allocations().Info(allocID, qOpts)
+	if err != nil {
+		return fmt.Errorf("error reading allocation %q: %v", allocID, err)
+	}
+
+	d.SetId(alloc.ID)
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("namespace", alloc.Namespace)
+	sw.Set("eval_id", alloc.EvalID)
+	sw.Set("name", alloc.Name)
+	sw.Set("node_id", alloc.NodeID)
+	sw.Set("node_name", alloc.NodeName)
+	sw.Set("job_id", alloc.JobID)
+	sw.Set("task_group", alloc.TaskGroup)
+	sw.Set("client_status", alloc.ClientStatus)
+	sw.Set("client_description", alloc.ClientDescription)
+	sw.Set("desired_status", alloc.DesiredStatus)
+	sw.Set("desired_description", alloc.DesiredDescription)
+	sw.Set("deployment_id", alloc.DeploymentID)
+	sw.Set("followup_eval_id", alloc.FollowupEvalID)
+	sw.Set("previous_allocation", alloc.PreviousAllocation)
+	sw.Set("next_allocation", alloc.NextAllocation)
+	sw.Set("create_time", formatUnixNano(alloc.CreateTime))
+	sw.Set("modify_time", formatUnixNano(alloc.ModifyTime))
+	sw.Set("address", allocationAddress(alloc.AllocatedResources))
+	sw.Set("ports", allocatedPortsRaw(alloc.AllocatedResources))
+	sw.Set("deployment_status", allocDeploymentStatusRaw(alloc.DeploymentStatus))
+	sw.Set("reschedule_events", rescheduleEventsRaw(alloc.RescheduleTracker))
+	sw.Set("task_states", taskStatesRaw(alloc.TaskStates))
+
+	jobVersion := 0
+	if alloc.Job != nil && alloc.Job.Version != nil {
+		jobVersion = int(*alloc.Job.Version)
+	}
+	sw.Set("job_version", jobVersion)
+
+	return sw.Error()
+}
+
+// allocationAddress returns the IP address of the first network of the
+// allocation, looking at the group networks before the task networks.
+func allocationAddress(resources *api.AllocatedResources) string {
+	if resources == nil {
+		return ""
+	}
+
+	for _, n := range resources.Shared.Networks {
+		if n != nil && n.IP != "" {
+			return n.IP
+		}
+	}
+
+	tasks := make([]string, 0, len(resources.Tasks))
+	for name := range resources.Tasks {
+		tasks = append(tasks, name)
+	}
+	sort.Strings(tasks)
+	for _, name := range tasks {
+		task := resources.Tasks[name]
+		if task == nil {
+			continue
+		}
+		for _, n := range task.Networks {
+			if n != nil && n.IP != "" {
+				return n.IP
+			}
+		}
+	}
+
+	return ""
+}
+
+func allocDeploymentStatusRaw(status *api.AllocDeploymentStatus) []interface{} {
+	if status == nil {
+		return nil
+	}
+
+	raw := map[string]interface{}{
+		"healthy":           status.Healthy != nil && *status.Healthy,
+		"health_determined": status.Healthy != nil,
+		"canary":            status.Canary,
+		"timestamp":         "",
+	}
+	if !status.Timestamp.IsZero() {
+		raw["timestamp"] = status.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+	return []interface{}{raw}
+}
+
+func rescheduleEventsRaw(tracker *api.RescheduleTracker) []interface{} {
+	if tracker == nil {
+		return nil
+	}
+
+	ret := make([]interface{}, 0, len(tracker.Events))
+	for _, e := range tracker.Events {
+		if e == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"prev_alloc_id":   e.PrevAllocID,
+			"prev_node_id":    e.PrevNodeID,
+			"reschedule_time": formatUnixNano(e.RescheduleTime),
+		})
+	}
+	return ret
+}
+
+// taskStatesRaw flattens the task states into a list sorted by task name.
+func taskStatesRaw(states map[string]*api.TaskState) []interface{} {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	ret := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		state := states[name]
+		if state == nil {
+			continue
+		}
+
+		events := make([]interface{}, 0, len(state.Events))
+		for _, e := range state.Events {
+			if e == nil {
+				continue
+			}
+			events = append(events, map[string]interface{}{
+				"type":    e.Type,
+				"time":    formatUnixNano(e.Time),
+				"message": e.DisplayMessage,
+				"details": e.Details,
+			})
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"name":         name,
+			"state":        state.State,
+			"failed":       state.Failed,
+			"restarts":     int(state.Restarts),
+			"last_restart": formatTime(state.LastRestart),
+			"started_at":   formatTime(state.StartedAt),
+			"finished_at":  formatTime(state.FinishedAt),
+			"events":       events,
+		})
+	}
+	return ret
+}
@@ -0,0 +1,202 @@
+package nomad
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceAllocation_basic(t *testing.T) {
+	dataSourceName := "data.nomad_allocation.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAllocationConfig("nomad_job.test.allocation_ids[0]"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "nomad_job.test", "allocation_ids.0"),
+					resource.TestCheckResourceAttr(dataSourceName, "job_id", "tf-allocation"),
+					resource.TestCheckResourceAttr(dataSourceName, "namespace", "default"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_group", "web"),
+					resource.TestCheckResourceAttr(dataSourceName, "client_status", "running"),
+					resource.TestCheckResourceAttr(dataSourceName, "job_version", "0"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "address"),
+					resource.TestCheckResourceAttr(dataSourceName, "ports.0.label", "http"),
+					resource.TestCheckResourceAttr(dataSourceName, "deployment_status.0.healthy", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_states.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_states.0.name", "web"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_states.0.state", "running"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_states.0.failed", "false"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "task_states.0.events.0.type"),
+				),
+			},
+			{
+				// Look up the allocation by prefix.
+				Config: testAccDataSourceAllocationConfig("substr(nomad_job.test.allocation_ids[0], 0, 8)"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "nomad_job.test", "allocation_ids.0"),
+					testAccDataSourceAllocationCheckAddress(dataSourceName),
+				),
+			},
+			{
+				Config:      testAccDataSourceAllocationConfig(`"ffffffff-0000"`),
+				ExpectError: regexp.MustCompile("no allocation found"),
+			},
+		},
+		CheckDestroy: testResourceJob_forceDestroyWithPurge("tf-allocation", "default"),
+	})
+}
+
+func testAccDataSourceAllocationConfig(allocID string) string {
+	return fmt.Sprintf(`
+resource "nomad_job" "test" {
+  detach  = false
+  jobspec = <<EOT
+job "tf-allocation" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    network {
+      port "http" {}
+    }
+
+    task "web" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 32
+      }
+    }
+  }
+}
+EOT
+}
+
+data "nomad_allocation" "test" {
+  allocation_id = %s
+}
+`, allocID)
+}
+
+// The address of the allocation is the host IP of its ports.
+func testAccDataSourceAllocationCheckAddress(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("data source %q not found", name)
+		}
+		address, hostIP := rs.Primary.Attributes["address"], rs.Primary.Attributes["ports.0.host_ip"]
+		if address != hostIP {
+			return fmt.Errorf("address is %q, expected %q", address, hostIP)
+		}
+		return nil
+	}
+}
+
+func TestAllocationAddress(t *testing.T) {
+	require.Equal(t, "", allocationAddress(nil))
+
+	// Group networks take precedence over task networks.
+	require.Equal(t, "10.0.0.1", allocationAddress(&api.AllocatedResources{
+		Shared: api.AllocatedSharedResources{
+			Networks: []*api.NetworkResource{{IP: "10.0.0.1"}},
+		},
+		Tasks: map[string]*api.AllocatedTaskResources{
+			"web": {Networks: []*api.NetworkResource{{IP: "10.0.0.2"}}},
+		},
+	}))
+
+	require.Equal(t, "10.0.0.2", allocationAddress(&api.AllocatedResources{
+		Tasks: map[string]*api.AllocatedTaskResources{
+			"web":   {Networks: []*api.NetworkResource{{IP: "10.0.0.2"}}},
+			"proxy": {},
+		},
+	}))
+}
+
+func TestAllocDeploymentStatusRaw(t *testing.T) {
+	require.Nil(t, allocDeploymentStatusRaw(nil))
+
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"healthy":           false,
+			"health_determined": false,
+			"canary":            true,
+			"timestamp":         "",
+		},
+	}, allocDeploymentStatusRaw(&api.AllocDeploymentStatus{Canary: true}))
+
+	ts := time.Date(2021, 5, 17, 20, 23, 21, 0, time.UTC)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"healthy":           true,
+			"health_determined": true,
+			"canary":            false,
+			"timestamp":         "2021-05-17T20:23:21Z",
+		},
+	}, allocDeploymentStatusRaw(&api.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true), Timestamp: ts}))
+}
+
+func TestTaskStatesRaw(t *testing.T) {
+	started := time.Date(2021, 5, 17, 20, 23, 21, 0, time.UTC)
+	states := map[string]*api.TaskState{
+		"web": {
+			State:     "running",
+			Restarts:  1,
+			StartedAt: started,
+			Events: []*api.TaskEvent{
+				{Type: api.TaskStarted, Time: started.UnixNano(), DisplayMessage: "Task started by client"},
+			},
+		},
+		"init": {
+			State:  "dead",
+			Failed: true,
+		},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"name":         "init",
+			"state":        "dead",
+			"failed":       true,
+			"restarts":     0,
+			"last_restart": "",
+			"started_at":   "",
+			"finished_at":  "",
+			"events":       []interface{}{},
+		},
+		map[string]interface{}{
+			"name":         "web",
+			"state":        "running",
+			"failed":       false,
+			"restarts":     1,
+			"last_restart": "",
+			"started_at":   "2021-05-17T20:23:21Z",
+			"finished_at":  "",
+			"events": []interface{}{
+				map[string]interface{}{
+					"type":    api.TaskStarted,
+					"time":    "2021-05-17T20:23:21Z",
+					"message": "Task started by client",
+					"details": map[string]string(nil),
+				},
+			},
+		},
+	}
+	require.Equal(t, expected, taskStatesRaw(states))
+}
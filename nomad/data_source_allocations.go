@@ -0,0 +1,241 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceAllocations() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAllocationsRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Description: "Namespace of the allocations. Use `*` for all namespaces.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"prefix": {
+				Description: "Prefix of the allocation IDs.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"job_id": {
+				Description: "Only return the allocations of this job.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"task_group": {
+				Description: "Only return the allocations of this task group.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"node_id": {
+				Description: "Only return the allocations placed on this node.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"client_status": {
+				Description: "Only return the allocations with this client status.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"desired_status": {
+				Description: "Only return the allocations with this desired status.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"allocations": {
+				Description: "The allocations matching the filters.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"eval_id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"name": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"namespace": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"job_id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"job_type": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"job_version": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"task_group": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"node_id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"node_name": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"client_status": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"client_description": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"desired_status": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"desired_description": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"followup_eval_id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"create_index": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"modify_index": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"create_time": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"modify_time": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"ports": allocatedPortsSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+// allocationsFilter holds the optional filters of the nomad_allocations data
+// source. Empty values match every allocation.
+type allocationsFilter struct {
+	JobID         string
+	TaskGroup     string
+	NodeID        string
+	ClientStatus  string
+	DesiredStatus string
+}
+
+func dataSourceAllocationsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	qOpts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+		Prefix:    d.Get("prefix").(string),
+		// Include the allocated resources to expose the ports.
+		Params: map[string]string{"resources": "true"},
+	}
+
+	log.Printf("[DEBUG] listing allocations in namespace %q", qOpts.Namespace)
+	stubs, _, err := client.Allocations().List(qOpts)
+	if err != nil {
+		return fmt.Errorf("error listing allocations: %v", err)
+	}
+
+	filter := allocationsFilter{
+		JobID:         d.Get("job_id").(string),
+		TaskGroup:     d.Get("task_group").(string),
+		NodeID:        d.Get("node_id").(string),
+		ClientStatus:  d.Get("client_status").(string),
+		DesiredStatus: d.Get("desired_status").(string),
+	}
+
+	allocs := make([]interface{}, 0, len(stubs))
+	for _, a := range filterAllocations(stubs, filter) {
+		allocs = append(allocs, allocationStubRaw(a))
+	}
+
+	d.SetId(resource.UniqueId())
+	if err := d.Set("allocations", allocs); err != nil {
+		return fmt.Errorf("error setting allocations: %v", err)
+	}
+
+	return nil
+}
+
+func filterAllocations(stubs []*api.AllocationListStub, filter allocationsFilter) []*api.AllocationListStub {
+	match := func(want, got string) bool {
+		return want == "" || want == got
+	}
+
+	ret := make([]*api.AllocationListStub, 0, len(stubs))
+	for _, a := range stubs {
+		if match(filter.JobID, a.JobID) &&
+			match(filter.TaskGroup, a.TaskGroup) &&
+			match(filter.NodeID, a.NodeID) &&
+			match(filter.ClientStatus, a.ClientStatus) &&
+			match(filter.DesiredStatus, a.DesiredStatus) {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+func allocationStubRaw(a *api.AllocationListStub) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  a.ID,
+		"eval_id":             a.EvalID,
+		"name":                a.Name,
+		"namespace":           a.Namespace,
+		"job_id":              a.JobID,
+		"job_type":            a.JobType,
+		"job_version":         int(a.JobVersion),
+		"task_group":          a.TaskGroup,
+		"node_id":             a.NodeID,
+		"node_name":           a.NodeName,
+		"client_status":       a.ClientStatus,
+		"client_description":  a.ClientDescription,
+		"desired_status":      a.DesiredStatus,
+		"desired_description": a.DesiredDescription,
+		"followup_eval_id":    a.FollowupEvalID,
+		"create_index":        int(a.CreateIndex),
+		"modify_index":        int(a.ModifyIndex),
+		"create_time":         formatUnixNano(a.CreateTime),
+		"modify_time":         formatUnixNano(a.ModifyTime),
+		"ports":               allocatedPortsRaw(a.AllocatedResources),
+	}
+}
+
+// formatUnixNano formats a timestamp in nanoseconds, as returned by the Nomad
+// API, using RFC 3339.
+func formatUnixNano(ns int64) string {
+	if ns == 0 {
+		return ""
+	}
+	return time.Unix(0, ns).UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,137 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceAllocations_basic(t *testing.T) {
+	dataSourceName := "data.nomad_allocations.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAllocationsConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.0.job_id", "tf-allocations"),
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.0.namespace", "default"),
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.0.task_group", "web"),
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.0.job_version", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.0.client_status", "running"),
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.0.ports.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "allocations.0.ports.0.label", "http"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "allocations.0.ports.0.host_ip"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "allocations.0.node_name"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "allocations.0.create_time"),
+				),
+			},
+		},
+		CheckDestroy: testResourceJob_forceDestroyWithPurge("tf-allocations", "default"),
+	})
+}
+
+const testAccDataSourceAllocationsConfig = `
+resource "nomad_job" "test" {
+  detach  = false
+  jobspec = <<EOT
+job "tf-allocations" {
+  datacenters = ["dc1"]
+
+  group "web" {
+    count = 2
+
+    network {
+      port "http" {}
+    }
+
+    task "web" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 32
+      }
+    }
+  }
+}
+EOT
+}
+
+data "nomad_allocations" "test" {
+  namespace     = "*"
+  job_id        = nomad_job.test.id
+  task_group    = "web"
+  client_status = "running"
+}
+`
+
+func TestFilterAllocations(t *testing.T) {
+	stubs := []*api.AllocationListStub{
+		{ID: "1", JobID: "web", TaskGroup: "server", NodeID: "node-1", ClientStatus: "running", DesiredStatus: "run"},
+		{ID: "2", JobID: "web", TaskGroup: "server", NodeID: "node-2", ClientStatus: "complete", DesiredStatus: "stop"},
+		{ID: "3", JobID: "web", TaskGroup: "proxy", NodeID: "node-1", ClientStatus: "running", DesiredStatus: "run"},
+		{ID: "4", JobID: "db", TaskGroup: "server", NodeID: "node-2", ClientStatus: "running", DesiredStatus: "run"},
+	}
+
+	cases := []struct {
+		name   string
+		filter allocationsFilter
+		want   []string
+	}{
+		{
+			name: "no filter",
+			want: []string{"1", "2", "3", "4"},
+		},
+		{
+			name:   "job",
+			filter: allocationsFilter{JobID: "web"},
+			want:   []string{"1", "2", "3"},
+		},
+		{
+			name:   "job and task group",
+			filter: allocationsFilter{JobID: "web", TaskGroup: "server"},
+			want:   []string{"1", "2"},
+		},
+		{
+			name:   "node",
+			filter: allocationsFilter{NodeID: "node-2"},
+			want:   []string{"2", "4"},
+		},
+		{
+			name:   "statuses",
+			filter: allocationsFilter{ClientStatus: "running", DesiredStatus: "run"},
+			want:   []string{"1", "3", "4"},
+		},
+		{
+			name:   "no match",
+			filter: allocationsFilter{JobID: "cache"},
+			want:   []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := []string{}
+			for _, a := range filterAllocations(stubs, tc.filter) {
+				got = append(got, a.ID)
+			}
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFormatUnixNano(t *testing.T) {
+	require.Equal(t, "", formatUnixNano(0))
+	require.Equal(t, "2021-05-17T20:23:21.5Z", formatUnixNano(1621283001500000000))
+}
@@ -0,0 +1,160 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+// autopilotUnhealthyPrefix starts the error returned by the API client when
+// Nomad answers the autopilot health endpoint with a 429, which it does
+// whenever the cluster is unhealthy. The health report is still in the body.
+const autopilotUnhealthyPrefix = "Unexpected response code: 429 ("
+
+func dataSourceAutopilotHealth() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAutopilotHealthRead,
+
+		Schema: map[string]*schema.Schema{
+			"healthy": {
+				Description: "Whether all the servers are healthy.",
+				Computed:    true,
+				Type:        schema.TypeBool,
+			},
+			"failure_tolerance": {
+				Description: "Number of servers that could be lost without an outage.",
+				Computed:    true,
+				Type:        schema.TypeInt,
+			},
+			"servers": {
+				Description: "The health of each server.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"name": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"address": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"serf_status": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"version": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"leader": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"voter": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"healthy": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"last_contact": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"last_term": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"last_index": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"stable_since": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAutopilotHealthRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	log.Printf("[DEBUG] Reading autopilot health")
+	health, _, err := client.Operator().AutopilotServerHealth(nil)
+	if err != nil {
+		var ok bool
+		if health, ok = autopilotHealthFromError(err); !ok {
+			return fmt.Errorf("error reading autopilot health: %v", err)
+		}
+	}
+	log.Printf("[DEBUG] Read autopilot health")
+
+	d.SetId(client.Address() + "/operator/autopilot/health")
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("healthy", health.Healthy)
+	sw.Set("failure_tolerance", health.FailureTolerance)
+	sw.Set("servers", serverHealthsRaw(health.Servers))
+	return sw.Error()
+}
+
+// autopilotHealthFromError decodes the health report embedded in the error
+// returned for an unhealthy cluster.
+func autopilotHealthFromError(err error) (*api.OperatorHealthReply, bool) {
+	msg := err.Error()
+	if !strings.HasPrefix(msg, autopilotUnhealthyPrefix) || !strings.HasSuffix(msg, ")") {
+		return nil, false
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(msg, autopilotUnhealthyPrefix), ")")
+
+	var health api.OperatorHealthReply
+	if err := json.Unmarshal([]byte(body), &health); err != nil {
+		return nil, false
+	}
+	return &health, true
+}
+
+func serverHealthsRaw(servers []api.ServerHealth) []interface{} {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	ret := make([]interface{}, 0, len(servers))
+	for _, s := range servers {
+		ret = append(ret, map[string]interface{}{
+			"id":           s.ID,
+			"name":         s.Name,
+			"address":      s.Address,
+			"serf_status":  s.SerfStatus,
+			"version":      s.Version,
+			"leader":       s.Leader,
+			"voter":        s.Voter,
+			"healthy":      s.Healthy,
+			"last_contact": s.LastContact.String(),
+			"last_term":    int(s.LastTerm),
+			"last_index":   int(s.LastIndex),
+			"stable_since": formatTime(s.StableSince),
+		})
+	}
+	return ret
+}
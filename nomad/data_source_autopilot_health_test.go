@@ -0,0 +1,100 @@
+package nomad
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceAutopilotHealth_basic(t *testing.T) {
+	dataSourceName := "data.nomad_autopilot_health.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `data "nomad_autopilot_health" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "healthy", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "failure_tolerance", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "servers.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "servers.0.healthy", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "servers.0.leader", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "servers.0.serf_status", "alive"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "servers.0.last_contact"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "servers.0.stable_since"),
+				),
+			},
+		},
+	})
+}
+
+func TestAutopilotHealthFromError(t *testing.T) {
+	t.Run("unhealthy", func(t *testing.T) {
+		err := errors.New(`Unexpected response code: 429 ({"Healthy":false,"FailureTolerance":0,"Servers":[{"ID":"a","Name":"server-1.global","Healthy":false,"LastContact":"2.5s"}]})`)
+
+		health, ok := autopilotHealthFromError(err)
+		require.True(t, ok)
+		require.False(t, health.Healthy)
+		require.Len(t, health.Servers, 1)
+		require.Equal(t, "server-1.global", health.Servers[0].Name)
+		require.Equal(t, 2500*time.Millisecond, health.Servers[0].LastContact)
+	})
+
+	t.Run("other status", func(t *testing.T) {
+		_, ok := autopilotHealthFromError(errors.New("Unexpected response code: 403 (Permission denied)"))
+		require.False(t, ok)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		_, ok := autopilotHealthFromError(errors.New("Unexpected response code: 429 (rate limited)"))
+		require.False(t, ok)
+	})
+}
+
+func TestServerHealthsRaw(t *testing.T) {
+	stableSince := time.Date(2021, 5, 17, 20, 23, 21, 0, time.FixedZone("CEST", 2*60*60))
+
+	raw := serverHealthsRaw([]api.ServerHealth{
+		{
+			ID:          "a",
+			Name:        "server-1.global",
+			Address:     "10.0.0.1:4647",
+			SerfStatus:  "alive",
+			Version:     "1.1.0",
+			Leader:      true,
+			Voter:       true,
+			Healthy:     true,
+			LastContact: 0,
+			LastTerm:    3,
+			LastIndex:   42,
+			StableSince: stableSince,
+		},
+		{
+			ID:          "b",
+			Name:        "server-2.global",
+			SerfStatus:  "failed",
+			LastContact: 1500 * time.Millisecond,
+		},
+	})
+
+	require.Len(t, raw, 2)
+
+	s := raw[0].(map[string]interface{})
+	require.Equal(t, "server-1.global", s["name"])
+	require.Equal(t, true, s["healthy"])
+	require.Equal(t, "0s", s["last_contact"])
+	require.Equal(t, 3, s["last_term"])
+	require.Equal(t, 42, s["last_index"])
+	require.Equal(t, "2021-05-17T18:23:21Z", s["stable_since"])
+
+	s = raw[1].(map[string]interface{})
+	require.Equal(t, false, s["healthy"])
+	require.Equal(t, "1.5s", s["last_contact"])
+	require.Equal(t, "", s["stable_since"])
+}
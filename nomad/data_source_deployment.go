@@ -0,0 +1,180 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceDeployment() *schema.Resource {
+	// The ID of the deployment is the ID of the data source.
+	s := deploymentSchema()
+	delete(s, "id")
+	s["deployment_id"] = &schema.Schema{
+		Description:  "ID of the deployment.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"deployment_id", "job_id"},
+	}
+	s["job_id"] = &schema.Schema{
+		Description:  "ID of the job to get the latest deployment of.",
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"deployment_id", "job_id"},
+	}
+	s["namespace"] = &schema.Schema{
+		Description: "Namespace of the deployment.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "default",
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceDeploymentRead,
+		Schema: s,
+	}
+}
+
+// deploymentSchema returns the attributes shared by the deployments of the
+// nomad_deployments and nomad_deployment data sources.
+func deploymentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id":                 computedString(),
+		"namespace":          computedString(),
+		"job_id":             computedString(),
+		"job_version":        computedInt(),
+		"job_modify_index":   computedInt(),
+		"job_create_index":   computedInt(),
+		"is_multiregion":     computedBool(),
+		"status":             computedString(),
+		"status_description": computedString(),
+		"create_index":       computedInt(),
+		"modify_index":       computedInt(),
+		"task_groups": {
+			Description: "State of the deployment of each task group.",
+			Computed:    true,
+			Type:        schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"name":                computedString(),
+					"auto_revert":         computedBool(),
+					"promoted":            computedBool(),
+					"progress_deadline":   computedString(),
+					"require_progress_by": computedString(),
+					"desired_canaries":    computedInt(),
+					"placed_canaries": {
+						Computed: true,
+						Type:     schema.TypeList,
+						Elem:     &schema.Schema{Type: schema.TypeString},
+					},
+					"desired_total":    computedInt(),
+					"placed_allocs":    computedInt(),
+					"healthy_allocs":   computedInt(),
+					"unhealthy_allocs": computedInt(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDeploymentRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	qOpts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+
+	var deployment *api.Deployment
+	var err error
+	if id := d.Get("deployment_id").(string); id != "" {
+		log.Printf("[DEBUG] reading deployment %q", id)
+		deployment, _, err = client.Deployments().Info(id, qOpts)
+		if err != nil {
+			return fmt.Errorf("error reading deployment %q: %v", id, err)
+		}
+	} else {
+		jobID := d.Get("job_id").(string)
+		log.Printf("[DEBUG] reading latest deployment of job %q", jobID)
+		deployment, _, err = client.Jobs().LatestDeployment(jobID, qOpts)
+		if err != nil {
+			return fmt.Errorf("error reading latest deployment of job %q: %v", jobID, err)
+		}
+		if deployment == nil {
+			return fmt.Errorf("job %q has no deployment", jobID)
+		}
+	}
+
+	d.SetId(deployment.ID)
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("deployment_id", deployment.ID)
+	for k, v := range deploymentRaw(deployment) {
+		if k != "id" {
+			sw.Set(k, v)
+		}
+	}
+
+	return sw.Error()
+}
+
+func deploymentRaw(d *api.Deployment) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                 d.ID,
+		"namespace":          d.Namespace,
+		"job_id":             d.JobID,
+		"job_version":        int(d.JobVersion),
+		"job_modify_index":   int(d.JobModifyIndex),
+		"job_create_index":   int(d.JobCreateIndex),
+		"is_multiregion":     d.IsMultiregion,
+		"status":             d.Status,
+		"status_description": d.StatusDescription,
+		"create_index":       int(d.CreateIndex),
+		"modify_index":       int(d.ModifyIndex),
+		"task_groups":        deploymentTaskGroupsRaw(d.TaskGroups),
+	}
+}
+
+// deploymentTaskGroupsRaw flattens the state of the task groups of a
+// deployment into a list sorted by task group.
+func deploymentTaskGroupsRaw(states map[string]*api.DeploymentState) []interface{} {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		state := states[name]
+		if state == nil {
+			continue
+		}
+
+		requireProgressBy := ""
+		if !state.RequireProgressBy.IsZero() {
+			requireProgressBy = state.RequireProgressBy.UTC().Format(time.RFC3339Nano)
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"name":                name,
+			"auto_revert":         state.AutoRevert,
+			"promoted":            state.Promoted,
+			"progress_deadline":   state.ProgressDeadline.String(),
+			"require_progress_by": requireProgressBy,
+			"desired_canaries":    state.DesiredCanaries,
+			"placed_canaries":     state.PlacedCanaries,
+			"desired_total":       state.DesiredTotal,
+			"placed_allocs":       state.PlacedAllocs,
+			"healthy_allocs":      state.HealthyAllocs,
+			"unhealthy_allocs":    state.UnhealthyAllocs,
+		})
+	}
+	return ret
+}
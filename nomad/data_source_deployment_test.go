@@ -0,0 +1,148 @@
+package nomad
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceDeployment_basic(t *testing.T) {
+	dataSourceName := "data.nomad_deployment.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceDeploymentConfig + `
+data "nomad_deployment" "test" {
+  job_id = nomad_job.test.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "deployment_id", "nomad_job.test", "deployment_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "job_id", "tf-deployment"),
+					resource.TestCheckResourceAttr(dataSourceName, "job_version", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "status", "successful"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.name", "web"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.auto_revert", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.progress_deadline", "10m0s"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.desired_total", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.healthy_allocs", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.unhealthy_allocs", "0"),
+				),
+			},
+			{
+				Config: testAccDataSourceDeploymentConfig + `
+data "nomad_deployment" "test" {
+  deployment_id = nomad_job.test.deployment_id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "nomad_job.test", "deployment_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "job_id", "tf-deployment"),
+				),
+			},
+			{
+				Config: `
+data "nomad_deployment" "test" {
+  job_id = "tf-does-not-exist"
+}
+`,
+				ExpectError: regexp.MustCompile(`job "tf-does-not-exist" has no deployment`),
+			},
+		},
+		CheckDestroy: testResourceJob_forceDestroyWithPurge("tf-deployment", "default"),
+	})
+}
+
+var testAccDataSourceDeploymentConfig = `
+resource "nomad_job" "test" {
+  detach  = false
+  jobspec = <<EOT
+job "tf-deployment" {
+  datacenters = ["dc1"]
+
+  update {
+    auto_revert       = true
+    min_healthy_time  = "1s"
+    progress_deadline = "10m"
+  }
+
+  group "web" {
+    count = 2
+
+    task "web" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 32
+      }
+    }
+  }
+}
+EOT
+}
+`
+
+func TestDeploymentTaskGroupsRaw(t *testing.T) {
+	requireProgressBy := time.Date(2021, 5, 17, 20, 33, 21, 0, time.UTC)
+	states := map[string]*api.DeploymentState{
+		"web": {
+			AutoRevert:        true,
+			ProgressDeadline:  10 * time.Minute,
+			RequireProgressBy: requireProgressBy,
+			DesiredCanaries:   1,
+			PlacedCanaries:    []string{"a"},
+			DesiredTotal:      3,
+			PlacedAllocs:      1,
+			UnhealthyAllocs:   1,
+		},
+		"api": {
+			Promoted:      true,
+			DesiredTotal:  1,
+			PlacedAllocs:  1,
+			HealthyAllocs: 1,
+		},
+	}
+
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":                "api",
+			"auto_revert":         false,
+			"promoted":            true,
+			"progress_deadline":   "0s",
+			"require_progress_by": "",
+			"desired_canaries":    0,
+			"placed_canaries":     []string(nil),
+			"desired_total":       1,
+			"placed_allocs":       1,
+			"healthy_allocs":      1,
+			"unhealthy_allocs":    0,
+		},
+		map[string]interface{}{
+			"name":                "web",
+			"auto_revert":         true,
+			"promoted":            false,
+			"progress_deadline":   "10m0s",
+			"require_progress_by": "2021-05-17T20:33:21Z",
+			"desired_canaries":    1,
+			"placed_canaries":     []string{"a"},
+			"desired_total":       3,
+			"placed_allocs":       1,
+			"healthy_allocs":      0,
+			"unhealthy_allocs":    1,
+		},
+	}, deploymentTaskGroupsRaw(states))
+}
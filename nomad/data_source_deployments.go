@@ -3,56 +3,112 @@ package nomad
 import (
 	"fmt"
 	"log"
-	"strconv"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
 func dataSourceDeployments() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceDeploymentsRead,
-		Schema: map[string]*schema.Schema{
 
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Description: "Namespace of the deployments. Use `*` for all namespaces.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"prefix": {
+				Description: "Prefix of the deployment IDs.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"job_id": {
+				Description: "Only return the deployments of this job.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"status": {
+				Description: "Only return the deployments with this status.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
 			"deployments": {
-				Description: "Deployments",
+				Description: "The deployments matching the filters.",
 				Computed:    true,
 				Type:        schema.TypeList,
-				Elem:        &schema.Schema{Type: schema.TypeMap},
+				Elem: &schema.Resource{
+					Schema: deploymentSchema(),
+				},
 			},
 		},
 	}
 }
 
+// deploymentsFilter holds the optional filters of the nomad_deployments data
+// source. Empty values match every deployment.
+type deploymentsFilter struct {
+	Prefix string
+	JobID  string
+	Status string
+}
+
 func dataSourceDeploymentsRead(d *schema.ResourceData, meta interface{}) error {
-	providerConfig := meta.(ProviderConfig)
-	client := providerConfig.client
+	client := meta.(ProviderConfig).client
 
-	log.Printf("[DEBUG] Getting deployments...")
-	deployment_list, _, err := client.Deployments().List(nil)
-	if err != nil {
-		// As of Nomad 0.4.1, the API client returns an error for 404
-		// rather than a nil result, so we must check this way.
-		if strings.Contains(err.Error(), "404") {
-			return err
-		}
+	qOpts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+	filter := deploymentsFilter{
+		Prefix: d.Get("prefix").(string),
+		JobID:  d.Get("job_id").(string),
+		Status: d.Get("status").(string),
+	}
 
-		return fmt.Errorf("error checking for deployments: %#v", err)
+	var deployments []*api.Deployment
+	var err error
+	if filter.JobID != "" && qOpts.Namespace != "*" {
+		// Only fetch the deployments of the job instead of listing all of
+		// the deployments of the namespace.
+		log.Printf("[DEBUG] listing deployments of job %q", filter.JobID)
+		deployments, _, err = client.Jobs().Deployments(filter.JobID, true, qOpts)
+	} else {
+		log.Printf("[DEBUG] listing deployments in namespace %q", qOpts.Namespace)
+		qOpts.Prefix = filter.Prefix
+		deployments, _, err = client.Deployments().List(qOpts)
+	}
+	if err != nil {
+		return fmt.Errorf("error listing deployments: %v", err)
 	}
 
-	var deployments []map[string]interface{}
+	result := make([]interface{}, 0, len(deployments))
+	for _, deployment := range filterDeployments(deployments, filter) {
+		result = append(result, deploymentRaw(deployment))
+	}
 
-	for _, deployment := range deployment_list {
-		entry := make(map[string]interface{})
-		entry["ID"] = deployment.ID
-		entry["JobID"] = deployment.JobID
-		entry["JobVersion"] = strconv.Itoa(int(deployment.JobVersion))
-		entry["Status"] = deployment.Status
-		entry["StatusDescription"] = deployment.StatusDescription
-		deployments = append(deployments, entry)
+	d.SetId(resource.UniqueId())
+	if err := d.Set("deployments", result); err != nil {
+		return fmt.Errorf("error setting deployments: %v", err)
 	}
 
-	d.SetId(client.Address() + "/deployments")
+	return nil
+}
+
+func filterDeployments(deployments []*api.Deployment, filter deploymentsFilter) []*api.Deployment {
+	match := func(want, got string) bool {
+		return want == "" || want == got
+	}
 
-	return d.Set("deployments", deployments)
+	ret := make([]*api.Deployment, 0, len(deployments))
+	for _, deployment := range deployments {
+		if strings.HasPrefix(deployment.ID, filter.Prefix) &&
+			match(filter.JobID, deployment.JobID) &&
+			match(filter.Status, deployment.Status) {
+			ret = append(ret, deployment)
+		}
+	}
+	return ret
 }
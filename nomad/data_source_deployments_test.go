@@ -6,8 +6,10 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
 )
 
 // Test will
@@ -46,7 +48,7 @@ func TestAccDataSourceDeployments(t *testing.T) {
 			{
 				Config: testAccCheckDataSourceNomadDeploymentsCfg,
 				Check: func(s *terraform.State) error {
-					re := regexp.MustCompile(`^deployments.(\d+).JobID$`)
+					re := regexp.MustCompile(`^deployments.(\d+).job_id$`)
 					rs, _ := s.RootModule().Resources["data.nomad_deployments.foobar"]
 					is := rs.Primary
 					index := -1
@@ -60,7 +62,7 @@ func TestAccDataSourceDeployments(t *testing.T) {
 					if index < 0 {
 						return fmt.Errorf("did not find expected deployment for job 'foo_deploy'")
 					}
-					statusAttr := fmt.Sprintf("deployments.%d.Status", index)
+					statusAttr := fmt.Sprintf("deployments.%d.status", index)
 					if s, ok := is.Attributes[statusAttr]; !ok || s != "cancelled" {
 						if !ok {
 							return fmt.Errorf("did not find expected attributed '%v'", statusAttr)
@@ -70,6 +72,17 @@ func TestAccDataSourceDeployments(t *testing.T) {
 					return nil
 				},
 			},
+			{
+				Config: testAccCheckDataSourceNomadDeploymentsFilteredCfg,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nomad_deployments.foobar", "deployments.0.job_id", "foo_deploy"),
+					resource.TestCheckResourceAttr("data.nomad_deployments.foobar", "deployments.0.namespace", "default"),
+					resource.TestCheckResourceAttr("data.nomad_deployments.foobar", "deployments.0.status", "cancelled"),
+					resource.TestCheckResourceAttr("data.nomad_deployments.foobar", "deployments.0.job_version", "0"),
+					resource.TestCheckResourceAttr("data.nomad_deployments.foobar", "deployments.0.task_groups.0.name", "foo"),
+					resource.TestCheckResourceAttr("data.nomad_deployments.foobar", "deployments.0.task_groups.0.desired_total", "1"),
+				),
+			},
 		},
 
 		// Somewhat-abuse CheckDestroy to actually do our cleanup... :/
@@ -111,4 +124,61 @@ data "nomad_deployments" "foobar" {}
 
 `
 
+var testAccCheckDataSourceNomadDeploymentsFilteredCfg = `
+
+data "nomad_deployments" "foobar" {
+	job_id = "foo_deploy"
+	status = "cancelled"
+}
+
+`
+
 var testAccCheckDataSourceNomadDeploymentsCfgWithJob = testAccCheckDataSourceNomadDeploymentsJobCfg + testAccCheckDataSourceNomadDeploymentsCfg
+
+func TestFilterDeployments(t *testing.T) {
+	deployments := []*api.Deployment{
+		{ID: "a1", JobID: "web", Status: "successful"},
+		{ID: "a2", JobID: "web", Status: "running"},
+		{ID: "b1", JobID: "db", Status: "successful"},
+	}
+
+	ids := func(deployments []*api.Deployment) []string {
+		ret := []string{}
+		for _, d := range deployments {
+			ret = append(ret, d.ID)
+		}
+		return ret
+	}
+
+	cases := []struct {
+		name   string
+		filter deploymentsFilter
+		want   []string
+	}{
+		{
+			name: "no filter",
+			want: []string{"a1", "a2", "b1"},
+		},
+		{
+			name:   "prefix",
+			filter: deploymentsFilter{Prefix: "a"},
+			want:   []string{"a1", "a2"},
+		},
+		{
+			name:   "job and status",
+			filter: deploymentsFilter{JobID: "web", Status: "successful"},
+			want:   []string{"a1"},
+		},
+		{
+			name:   "no match",
+			filter: deploymentsFilter{Status: "failed"},
+			want:   []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, ids(filterDeployments(deployments, tc.filter)))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceEvaluation() *schema.Resource {
+	// The ID of the evaluation is the ID of the data source.
+	s := evaluationSchema()
+	delete(s, "id")
+	s["eval_id"] = &schema.Schema{
+		Description: "ID of the evaluation.",
+		Type:        schema.TypeString,
+		Required:    true,
+	}
+	s["namespace"] = &schema.Schema{
+		Description: "Namespace of the evaluation.",
+		Type:        schema.TypeString,
+		Optional:    true,
+		Default:     "default",
+	}
+	s["wait_until"] = &schema.Schema{
+		Description: "Date and time until which the evaluation is delayed.",
+		Computed:    true,
+		Type:        schema.TypeString,
+	}
+	s["quota_limit_reached"] = &schema.Schema{
+		Description: "The quota limit that prevented allocations from being placed, if any.",
+		Computed:    true,
+		Type:        schema.TypeString,
+	}
+	s["escaped_computed_class"] = &schema.Schema{
+		Description: "Whether the job has constraints that escape the computed node classes.",
+		Computed:    true,
+		Type:        schema.TypeBool,
+	}
+	s["class_eligibility"] = &schema.Schema{
+		Description: "Eligibility of the computed node classes for the job.",
+		Computed:    true,
+		Type:        schema.TypeMap,
+		Elem:        &schema.Schema{Type: schema.TypeBool},
+	}
+	s["queued_allocations"] = &schema.Schema{
+		Description: "Number of allocations queued for each task group.",
+		Computed:    true,
+		Type:        schema.TypeMap,
+		Elem:        &schema.Schema{Type: schema.TypeInt},
+	}
+	s["placement_failures"] = placementFailuresSchema()
+
+	return &schema.Resource{
+		Read:   dataSourceEvaluationRead,
+		Schema: s,
+	}
+}
+
+func dataSourceEvaluationRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	id := d.Get("eval_id").(string)
+	qOpts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+
+	log.Printf("[DEBUG] reading evaluation %q", id)
+	eval, _, err := client.Evaluations().Info(id, qOpts)
+	if err != nil {
+		return fmt.Errorf("error reading evaluation %q: %v", id, err)
+	}
+
+	d.SetId(eval.ID)
+
+	sw := helper.NewStateWriter(d)
+	for k, v := range evaluationRaw(eval) {
+		if k != "id" {
+			sw.Set(k, v)
+		}
+	}
+
+	waitUntil := ""
+	if !eval.WaitUntil.IsZero() {
+		waitUntil = eval.WaitUntil.UTC().Format(time.RFC3339Nano)
+	}
+	sw.Set("wait_until", waitUntil)
+	sw.Set("quota_limit_reached", eval.QuotaLimitReached)
+	sw.Set("escaped_computed_class", eval.EscapedComputedClass)
+	sw.Set("class_eligibility", eval.ClassEligibility)
+	sw.Set("queued_allocations", eval.QueuedAllocations)
+	sw.Set("placement_failures", placementFailuresRaw(eval.FailedTGAllocs))
+
+	return sw.Error()
+}
@@ -0,0 +1,45 @@
+package nomad
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccDataSourceEvaluation_basic(t *testing.T) {
+	dataSourceName := "data.nomad_evaluation.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceJobEvaluate_config("1", 1000000) + `
+data "nomad_evaluation" "test" {
+  eval_id = nomad_job_evaluate.test.eval_id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "nomad_job_evaluate.test", "eval_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "job_id", "tf-job-evaluate"),
+					resource.TestCheckResourceAttr(dataSourceName, "status", "complete"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "blocked_eval", "nomad_job_evaluate.test", "blocked_eval_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "queued_allocations.foo", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "placement_failures.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "placement_failures.0.task_group", "foo"),
+					resource.TestCheckResourceAttr(dataSourceName, "placement_failures.0.dimension_exhausted.memory", "1"),
+				),
+			},
+			{
+				Config: `
+data "nomad_evaluation" "test" {
+  eval_id = "00000000-0000-0000-0000-000000000000"
+}
+`,
+				ExpectError: regexp.MustCompile("error reading evaluation"),
+			},
+		},
+		CheckDestroy: testResourceJob_forceDestroyWithPurge("tf-job-evaluate", "default"),
+	})
+}
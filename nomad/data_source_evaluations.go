@@ -0,0 +1,156 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceEvaluations() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceEvaluationsRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Description: "Namespace of the evaluations. Use `*` for all namespaces.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"prefix": {
+				Description: "Prefix of the evaluation IDs.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"job_id": {
+				Description: "Only return the evaluations of this job.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"status": {
+				Description: "Only return the evaluations with this status.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"triggered_by": {
+				Description: "Only return the evaluations triggered by this event.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"evaluations": {
+				Description: "The evaluations matching the filters.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: evaluationSchema(),
+				},
+			},
+		},
+	}
+}
+
+// evaluationSchema returns the attributes shared by the evaluations of the
+// nomad_evaluations and nomad_evaluation data sources.
+func evaluationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id":                 computedString(),
+		"namespace":          computedString(),
+		"job_id":             computedString(),
+		"type":               computedString(),
+		"priority":           computedInt(),
+		"triggered_by":       computedString(),
+		"status":             computedString(),
+		"status_description": computedString(),
+		"node_id":            computedString(),
+		"deployment_id":      computedString(),
+		"previous_eval":      computedString(),
+		"next_eval":          computedString(),
+		"blocked_eval":       computedString(),
+		"create_index":       computedInt(),
+		"modify_index":       computedInt(),
+		"create_time":        computedString(),
+		"modify_time":        computedString(),
+	}
+}
+
+// evaluationsFilter holds the optional filters of the nomad_evaluations data
+// source. Empty values match every evaluation.
+type evaluationsFilter struct {
+	JobID       string
+	Status      string
+	TriggeredBy string
+}
+
+func dataSourceEvaluationsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	qOpts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+		Prefix:    d.Get("prefix").(string),
+	}
+
+	log.Printf("[DEBUG] listing evaluations in namespace %q", qOpts.Namespace)
+	evals, _, err := client.Evaluations().List(qOpts)
+	if err != nil {
+		return fmt.Errorf("error listing evaluations: %v", err)
+	}
+
+	filter := evaluationsFilter{
+		JobID:       d.Get("job_id").(string),
+		Status:      d.Get("status").(string),
+		TriggeredBy: d.Get("triggered_by").(string),
+	}
+
+	result := make([]interface{}, 0, len(evals))
+	for _, e := range filterEvaluations(evals, filter) {
+		result = append(result, evaluationRaw(e))
+	}
+
+	d.SetId(resource.UniqueId())
+	if err := d.Set("evaluations", result); err != nil {
+		return fmt.Errorf("error setting evaluations: %v", err)
+	}
+
+	return nil
+}
+
+func filterEvaluations(evals []*api.Evaluation, filter evaluationsFilter) []*api.Evaluation {
+	match := func(want, got string) bool {
+		return want == "" || want == got
+	}
+
+	ret := make([]*api.Evaluation, 0, len(evals))
+	for _, e := range evals {
+		if match(filter.JobID, e.JobID) &&
+			match(filter.Status, e.Status) &&
+			match(filter.TriggeredBy, e.TriggeredBy) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+func evaluationRaw(e *api.Evaluation) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                 e.ID,
+		"namespace":          e.Namespace,
+		"job_id":             e.JobID,
+		"type":               e.Type,
+		"priority":           e.Priority,
+		"triggered_by":       e.TriggeredBy,
+		"status":             e.Status,
+		"status_description": e.StatusDescription,
+		"node_id":            e.NodeID,
+		"deployment_id":      e.DeploymentID,
+		"previous_eval":      e.PreviousEval,
+		"next_eval":          e.NextEval,
+		"blocked_eval":       e.BlockedEval,
+		"create_index":       int(e.CreateIndex),
+		"modify_index":       int(e.ModifyIndex),
+		"create_time":        formatUnixNano(e.CreateTime),
+		"modify_time":        formatUnixNano(e.ModifyTime),
+	}
+}
@@ -0,0 +1,92 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceEvaluations_basic(t *testing.T) {
+	dataSourceName := "data.nomad_evaluations.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceJobEvaluate_config("1", 1000000) + `
+data "nomad_evaluations" "test" {
+  job_id = nomad_job_evaluate.test.job_id
+  status = "blocked"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "evaluations.#", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "evaluations.0.id", "nomad_job_evaluate.test", "blocked_eval_id"),
+					resource.TestCheckResourceAttr(dataSourceName, "evaluations.0.job_id", "tf-job-evaluate"),
+					resource.TestCheckResourceAttr(dataSourceName, "evaluations.0.namespace", "default"),
+					resource.TestCheckResourceAttr(dataSourceName, "evaluations.0.type", "service"),
+					resource.TestCheckResourceAttr(dataSourceName, "evaluations.0.triggered_by", "queued-allocs"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "evaluations.0.previous_eval"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "evaluations.0.create_time"),
+				),
+			},
+		},
+		CheckDestroy: testResourceJob_forceDestroyWithPurge("tf-job-evaluate", "default"),
+	})
+}
+
+func TestFilterEvaluations(t *testing.T) {
+	evals := []*api.Evaluation{
+		{ID: "1", JobID: "web", Status: "complete", TriggeredBy: "job-register"},
+		{ID: "2", JobID: "web", Status: "blocked", TriggeredBy: "queued-allocs"},
+		{ID: "3", JobID: "db", Status: "complete", TriggeredBy: "node-update"},
+	}
+
+	ids := func(evals []*api.Evaluation) []string {
+		ret := []string{}
+		for _, e := range evals {
+			ret = append(ret, e.ID)
+		}
+		return ret
+	}
+
+	cases := []struct {
+		name   string
+		filter evaluationsFilter
+		want   []string
+	}{
+		{
+			name: "no filter",
+			want: []string{"1", "2", "3"},
+		},
+		{
+			name:   "job",
+			filter: evaluationsFilter{JobID: "web"},
+			want:   []string{"1", "2"},
+		},
+		{
+			name:   "status",
+			filter: evaluationsFilter{Status: "complete"},
+			want:   []string{"1", "3"},
+		},
+		{
+			name:   "job and triggered by",
+			filter: evaluationsFilter{JobID: "web", TriggeredBy: "queued-allocs"},
+			want:   []string{"2"},
+		},
+		{
+			name:   "no match",
+			filter: evaluationsFilter{Status: "failed"},
+			want:   []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, ids(filterEvaluations(evals, tc.filter)))
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceJobSummary() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceJobSummaryRead,
+
+		Schema: map[string]*schema.Schema{
+			"job_id": {
+				Description: "ID of the job.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"namespace": {
+				Description: "Namespace of the job.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"task_groups": taskGroupSummariesSchema(),
+			"children":    jobChildrenSummarySchema(),
+			"create_index": {
+				Computed: true,
+				Type:     schema.TypeInt,
+			},
+			"modify_index": {
+				Computed: true,
+				Type:     schema.TypeInt,
+			},
+		},
+	}
+}
+
+func taskGroupSummariesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Number of allocations of each task group in each state.",
+		Computed:    true,
+		Type:        schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"queued":   computedInt(),
+				"starting": computedInt(),
+				"running":  computedInt(),
+				"complete": computedInt(),
+				"failed":   computedInt(),
+				"lost":     computedInt(),
+			},
+		},
+	}
+}
+
+func jobChildrenSummarySchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Number of child jobs in each state, for periodic and parameterized jobs.",
+		Computed:    true,
+		Type:        schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"pending": computedInt(),
+				"running": computedInt(),
+				"dead":    computedInt(),
+			},
+		},
+	}
+}
+
+func dataSourceJobSummaryRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	id := d.Get("job_id").(string)
+	ns := d.Get("namespace").(string)
+
+	log.Printf("[DEBUG] reading summary of job %q in namespace %q", id, ns)
+	summary, _, err := client.Jobs().Summary(id, &api.QueryOptions{
+		Namespace: ns,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading summary of job %q: %v", id, err)
+	}
+
+	d.SetId(summary.JobID)
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("task_groups", taskGroupSummariesRaw(summary.Summary))
+	sw.Set("children", jobChildrenSummaryRaw(summary.Children))
+	sw.Set("create_index", int(summary.CreateIndex))
+	sw.Set("modify_index", int(summary.ModifyIndex))
+
+	return sw.Error()
+}
+
+// taskGroupSummariesRaw flattens the summaries of the task groups into a list
+// sorted by task group.
+func taskGroupSummariesRaw(summaries map[string]api.TaskGroupSummary) []interface{} {
+	names := make([]string, 0, len(summaries))
+	for name := range summaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		s := summaries[name]
+		ret = append(ret, map[string]interface{}{
+			"name":     name,
+			"queued":   s.Queued,
+			"starting": s.Starting,
+			"running":  s.Running,
+			"complete": s.Complete,
+			"failed":   s.Failed,
+			"lost":     s.Lost,
+		})
+	}
+	return ret
+}
+
+func jobChildrenSummaryRaw(children *api.JobChildrenSummary) []interface{} {
+	if children == nil {
+		return nil
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"pending": int(children.Pending),
+			"running": int(children.Running),
+			"dead":    int(children.Dead),
+		},
+	}
+}
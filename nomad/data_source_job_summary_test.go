@@ -0,0 +1,145 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceJobSummary_basic(t *testing.T) {
+	dataSourceName := "data.nomad_job_summary.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceJobSummaryConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "id", "tf-job-summary"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.name", "foo"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.running", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.failed", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "task_groups.0.queued", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "children.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "children.0.running", "0"),
+				),
+			},
+			{
+				Config: testAccDataSourceJobSummaryConfig + `
+data "nomad_job_summary" "periodic" {
+  job_id = nomad_job.periodic.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.nomad_job_summary.periodic", "task_groups.#", "1"),
+					resource.TestCheckResourceAttr("data.nomad_job_summary.periodic", "task_groups.0.running", "0"),
+					resource.TestCheckResourceAttr("data.nomad_job_summary.periodic", "children.0.pending", "0"),
+				),
+			},
+		},
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testResourceJob_forceDestroyWithPurge("tf-job-summary", "default"),
+			testResourceJob_forceDestroyWithPurge("tf-job-summary-periodic", "default"),
+		),
+	})
+}
+
+var testAccDataSourceJobSummaryConfig = `
+resource "nomad_job" "test" {
+  detach  = false
+  jobspec = <<EOT
+job "tf-job-summary" {
+  datacenters = ["dc1"]
+
+  update {
+    min_healthy_time = "1s"
+  }
+
+  group "foo" {
+    count = 2
+
+    task "foo" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 32
+      }
+    }
+  }
+}
+EOT
+}
+
+resource "nomad_job" "periodic" {
+  jobspec = <<EOT
+job "tf-job-summary-periodic" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  periodic {
+    cron = "0 0 1 1 *"
+  }
+
+  group "foo" {
+    task "foo" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/echo"
+        args    = ["test"]
+      }
+    }
+  }
+}
+EOT
+}
+
+data "nomad_job_summary" "test" {
+  job_id = nomad_job.test.id
+}
+`
+
+func TestTaskGroupSummariesRaw(t *testing.T) {
+	summaries := map[string]api.TaskGroupSummary{
+		"web": {Running: 3, Failed: 1},
+		"api": {Queued: 2, Starting: 1, Complete: 4, Lost: 1},
+	}
+
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":     "api",
+			"queued":   2,
+			"starting": 1,
+			"running":  0,
+			"complete": 4,
+			"failed":   0,
+			"lost":     1,
+		},
+		map[string]interface{}{
+			"name":     "web",
+			"queued":   0,
+			"starting": 0,
+			"running":  3,
+			"complete": 0,
+			"failed":   1,
+			"lost":     0,
+		},
+	}, taskGroupSummariesRaw(summaries))
+}
+
+func TestJobChildrenSummaryRaw(t *testing.T) {
+	require.Nil(t, jobChildrenSummaryRaw(nil))
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"pending": 1, "running": 2, "dead": 3},
+	}, jobChildrenSummaryRaw(&api.JobChildrenSummary{Pending: 1, Running: 2, Dead: 3}))
+}
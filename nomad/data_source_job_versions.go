@@ -0,0 +1,234 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceJobVersions() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceJobVersionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"job_id": {
+				Description: "ID of the job.",
+				Type:        schema.TypeString,
+				Required:    true,
+			},
+			"namespace": {
+				Description: "Namespace of the job.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"last_stable_version": {
+				Description: "The most recent stable version of the job, or -1 if no version is stable.",
+				Computed:    true,
+				Type:        schema.TypeInt,
+			},
+			"versions": {
+				Description: "The versions of the job, most recent first.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"version": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"stable": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"submit_time": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"modify_index": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"job_modify_index": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"diff_type": {
+							Description: "Type of the changes from the previous version.",
+							Computed:    true,
+							Type:        schema.TypeString,
+						},
+						"diff": {
+							Description: "Summary of the changes from the previous version.",
+							Computed:    true,
+							Type:        schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceJobVersionsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	id := d.Get("job_id").(string)
+	ns := d.Get("namespace").(string)
+
+	log.Printf("[DEBUG] reading versions of job %q in namespace %q", id, ns)
+	versions, diffs, _, err := client.Jobs().Versions(id, true, &api.QueryOptions{
+		Namespace: ns,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading versions of job %q: %v", id, err)
+	}
+
+	d.SetId(id)
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("versions", jobVersionsRaw(versions, diffs))
+	sw.Set("last_stable_version", lastStableVersion(versions))
+
+	return sw.Error()
+}
+
+// jobVersionsRaw flattens the versions of a job. Nomad returns the versions
+// most recent first, and the diff at index i is between the versions i+1 and
+// i, so the oldest version has no diff.
+func jobVersionsRaw(versions []*api.Job, diffs []*api.JobDiff) []interface{} {
+	ret := make([]interface{}, 0, len(versions))
+	for i, job := range versions {
+		if job == nil {
+			continue
+		}
+
+		diffType, diff := "", ""
+		if i < len(diffs) && diffs[i] != nil {
+			diffType = diffs[i].Type
+			diff = renderJobDiff(diffs[i])
+		}
+
+		raw := map[string]interface{}{
+			"version":          0,
+			"stable":           job.Stable != nil && *job.Stable,
+			"submit_time":      "",
+			"modify_index":     0,
+			"job_modify_index": 0,
+			"diff_type":        diffType,
+			"diff":             diff,
+		}
+		if job.Version != nil {
+			raw["version"] = int(*job.Version)
+		}
+		if job.SubmitTime != nil {
+			raw["submit_time"] = formatUnixNano(*job.SubmitTime)
+		}
+		if job.ModifyIndex != nil {
+			raw["modify_index"] = int(*job.ModifyIndex)
+		}
+		if job.JobModifyIndex != nil {
+			raw["job_modify_index"] = int(*job.JobModifyIndex)
+		}
+		ret = append(ret, raw)
+	}
+	return ret
+}
+
+func lastStableVersion(versions []*api.Job) int {
+	last := -1
+	for _, job := range versions {
+		if job == nil || job.Stable == nil || !*job.Stable || job.Version == nil {
+			continue
+		}
+		if v := int(*job.Version); v > last {
+			last = v
+		}
+	}
+	return last
+}
+
+// renderJobDiff renders a job diff in a format close to the one used by the
+// `nomad job history -p` command, omitting what didn't change.
+func renderJobDiff(diff *api.JobDiff) string {
+	var b strings.Builder
+
+	renderDiffHeader(&b, 0, diff.Type, fmt.Sprintf("Job: %q", diff.ID))
+	renderFieldDiffs(&b, 1, diff.Fields)
+	renderObjectDiffs(&b, 1, diff.Objects)
+
+	for _, tg := range diff.TaskGroups {
+		if tg == nil || tg.Type == "None" {
+			continue
+		}
+		renderDiffHeader(&b, 1, tg.Type, fmt.Sprintf("Task Group: %q", tg.Name))
+		renderFieldDiffs(&b, 2, tg.Fields)
+		renderObjectDiffs(&b, 2, tg.Objects)
+
+		for _, task := range tg.Tasks {
+			if task == nil || task.Type == "None" {
+				continue
+			}
+			renderDiffHeader(&b, 2, task.Type, fmt.Sprintf("Task: %q", task.Name))
+			renderFieldDiffs(&b, 3, task.Fields)
+			renderObjectDiffs(&b, 3, task.Objects)
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func diffMarker(diffType string) string {
+	switch diffType {
+	case "Added":
+		return "+"
+	case "Deleted":
+		return "-"
+	case "Edited":
+		return "+/-"
+	default:
+		return ""
+	}
+}
+
+func renderDiffHeader(b *strings.Builder, indent int, diffType, header string) {
+	prefix := strings.Repeat("  ", indent)
+	if marker := diffMarker(diffType); marker != "" {
+		prefix += marker + " "
+	}
+	fmt.Fprintf(b, "%s%s\n", prefix, header)
+}
+
+func renderFieldDiffs(b *strings.Builder, indent int, fields []*api.FieldDiff) {
+	prefix := strings.Repeat("  ", indent)
+	for _, f := range fields {
+		if f == nil {
+			continue
+		}
+		switch f.Type {
+		case "Added":
+			fmt.Fprintf(b, "%s+ %s: %q\n", prefix, f.Name, f.New)
+		case "Deleted":
+			fmt.Fprintf(b, "%s- %s: %q\n", prefix, f.Name, f.Old)
+		case "Edited":
+			fmt.Fprintf(b, "%s+/- %s: %q => %q\n", prefix, f.Name, f.Old, f.New)
+		}
+	}
+}
+
+func renderObjectDiffs(b *strings.Builder, indent int, objects []*api.ObjectDiff) {
+	prefix := strings.Repeat("  ", indent)
+	for _, o := range objects {
+		if o == nil || o.Type == "None" {
+			continue
+		}
+		fmt.Fprintf(b, "%s%s %s {\n", prefix, diffMarker(o.Type), o.Name)
+		renderFieldDiffs(b, indent+1, o.Fields)
+		renderObjectDiffs(b, indent+1, o.Objects)
+		fmt.Fprintf(b, "%s}\n", prefix)
+	}
+}
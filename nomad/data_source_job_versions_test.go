@@ -0,0 +1,190 @@
+package nomad
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceJobVersions_basic(t *testing.T) {
+	dataSourceName := "data.nomad_job_versions.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceJobVersionsConfig(32),
+			},
+			{
+				Config: testAccDataSourceJobVersionsConfig(64),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "versions.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "versions.0.version", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "versions.0.diff_type", "Edited"),
+					resource.TestMatchResourceAttr(dataSourceName, "versions.0.diff", regexp.MustCompile(`\+/- MemoryMB: "32" => "64"`)),
+					resource.TestCheckResourceAttr(dataSourceName, "versions.1.version", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "versions.1.stable", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "versions.1.diff", ""),
+					resource.TestCheckResourceAttrSet(dataSourceName, "versions.1.submit_time"),
+					resource.TestCheckResourceAttr(dataSourceName, "last_stable_version", "1"),
+				),
+			},
+		},
+		CheckDestroy: testResourceJob_forceDestroyWithPurge("tf-job-versions", "default"),
+	})
+}
+
+func testAccDataSourceJobVersionsConfig(memory int) string {
+	return fmt.Sprintf(`
+resource "nomad_job" "test" {
+  detach  = false
+  jobspec = <<EOT
+job "tf-job-versions" {
+  datacenters = ["dc1"]
+
+  update {
+    min_healthy_time = "1s"
+  }
+
+  group "foo" {
+    task "foo" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = %d
+      }
+    }
+  }
+}
+EOT
+}
+
+data "nomad_job_versions" "test" {
+  job_id = nomad_job.test.id
+
+  depends_on = [nomad_job.test]
+}
+`, memory)
+}
+
+func TestJobVersionsRaw(t *testing.T) {
+	versions := []*api.Job{
+		{
+			Version:        helper.Uint64ToPtr(1),
+			Stable:         helper.BoolToPtr(false),
+			SubmitTime:     helper.Int64ToPtr(1621282401000000000),
+			ModifyIndex:    helper.Uint64ToPtr(20),
+			JobModifyIndex: helper.Uint64ToPtr(19),
+		},
+		{
+			Version: helper.Uint64ToPtr(0),
+			Stable:  helper.BoolToPtr(true),
+		},
+	}
+	diffs := []*api.JobDiff{
+		{
+			Type: "Edited",
+			ID:   "example",
+			Fields: []*api.FieldDiff{
+				{Type: "Edited", Name: "Priority", Old: "50", New: "60"},
+			},
+		},
+	}
+
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"version":          1,
+			"stable":           false,
+			"submit_time":      "2021-05-17T20:13:21Z",
+			"modify_index":     20,
+			"job_modify_index": 19,
+			"diff_type":        "Edited",
+			"diff":             "+/- Job: \"example\"\n  +/- Priority: \"50\" => \"60\"",
+		},
+		map[string]interface{}{
+			"version":          0,
+			"stable":           true,
+			"submit_time":      "",
+			"modify_index":     0,
+			"job_modify_index": 0,
+			"diff_type":        "",
+			"diff":             "",
+		},
+	}, jobVersionsRaw(versions, diffs))
+
+	require.Equal(t, 0, lastStableVersion(versions))
+	require.Equal(t, -1, lastStableVersion(versions[:1]))
+}
+
+func TestRenderJobDiff(t *testing.T) {
+	diff := &api.JobDiff{
+		Type: "Edited",
+		ID:   "example",
+		Fields: []*api.FieldDiff{
+			{Type: "None", Name: "Type", Old: "service", New: "service"},
+			{Type: "Added", Name: "Meta[owner]", New: "team-a"},
+		},
+		Objects: []*api.ObjectDiff{
+			{Type: "None", Name: "Datacenters"},
+			{
+				Type: "Edited",
+				Name: "Update",
+				Fields: []*api.FieldDiff{
+					{Type: "Edited", Name: "MaxParallel", Old: "1", New: "2"},
+				},
+			},
+		},
+		TaskGroups: []*api.TaskGroupDiff{
+			{Type: "None", Name: "unchanged"},
+			{
+				Type: "Edited",
+				Name: "web",
+				Fields: []*api.FieldDiff{
+					{Type: "Edited", Name: "Count", Old: "1", New: "3"},
+				},
+				Tasks: []*api.TaskDiff{
+					{
+						Type: "Edited",
+						Name: "server",
+						Objects: []*api.ObjectDiff{
+							{
+								Type: "Deleted",
+								Name: "Env",
+								Fields: []*api.FieldDiff{
+									{Type: "Deleted", Name: "DEBUG", Old: "1"},
+								},
+							},
+						},
+					},
+					{Type: "Added", Name: "sidecar"},
+				},
+			},
+		},
+	}
+
+	expected := `+/- Job: "example"
+  + Meta[owner]: "team-a"
+  +/- Update {
+    +/- MaxParallel: "1" => "2"
+  }
+  +/- Task Group: "web"
+    +/- Count: "1" => "3"
+    +/- Task: "server"
+      - Env {
+        - DEBUG: "1"
+      }
+    + Task: "sidecar"`
+	require.Equal(t, expected, renderJobDiff(diff))
+}
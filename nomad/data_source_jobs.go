@@ -0,0 +1,237 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// dataSourceJobsConcurrency is the number of jobs read at the same time to
+// get their version, which is not part of the job list.
+const dataSourceJobsConcurrency = 8
+
+func dataSourceJobs() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceJobsRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": {
+				Description: "Namespace of the jobs. Use `*` for all namespaces.",
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "default",
+			},
+			"prefix": {
+				Description: "Prefix of the job IDs.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"type": {
+				Description:  "Only return the jobs of this type.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"service", "batch", "system"}, false),
+			},
+			"status": {
+				Description:  "Only return the jobs with this status.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"pending", "running", "dead"}, false),
+			},
+			"parent_id": {
+				Description: "Only return the child jobs of this job.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"jobs": {
+				Description: "The jobs matching the filters.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"name": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"namespace": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"parent_id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"type": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"priority": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"status": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"status_description": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"stop": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"version": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"datacenters": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"periodic": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"parameterized": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"submit_time": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"modify_index": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+						"job_modify_index": {
+							Computed: true,
+							Type:     schema.TypeInt,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// jobsFilter holds the optional filters of the nomad_jobs data source. Empty
+// values match every job.
+type jobsFilter struct {
+	Type     string
+	Status   string
+	ParentID string
+}
+
+func dataSourceJobsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	qOpts := &api.QueryOptions{
+		Namespace: d.Get("namespace").(string),
+		Prefix:    d.Get("prefix").(string),
+	}
+
+	log.Printf("[DEBUG] listing jobs in namespace %q", qOpts.Namespace)
+	stubs, _, err := client.Jobs().List(qOpts)
+	if err != nil {
+		return fmt.Errorf("error listing jobs: %v", err)
+	}
+
+	stubs = filterJobs(stubs, jobsFilter{
+		Type:     d.Get("type").(string),
+		Status:   d.Get("status").(string),
+		ParentID: d.Get("parent_id").(string),
+	})
+
+	// The version of the jobs is not part of the job list, so each job is
+	// read to get it. Jobs purged in the meantime are ignored.
+	jobs := make([]map[string]interface{}, len(stubs))
+	err = forEachConcurrently(len(stubs), dataSourceJobsConcurrency, func(i int) error {
+		stub := stubs[i]
+		ns := stub.Namespace
+		if ns == "" {
+			ns = qOpts.Namespace
+		}
+		job, _, err := client.Jobs().Info(stub.ID, &api.QueryOptions{
+			Namespace: ns,
+		})
+		if err != nil {
+			if strings.Contains(err.Error(), "404") {
+				return nil
+			}
+			return fmt.Errorf("error reading job %q: %v", stub.ID, err)
+		}
+
+		raw := jobStubRaw(stub)
+		if job.Version != nil {
+			raw["version"] = int(*job.Version)
+		}
+		jobs[i] = raw
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result := make([]interface{}, 0, len(jobs))
+	for _, job := range jobs {
+		if job != nil {
+			result = append(result, job)
+		}
+	}
+
+	d.SetId(resource.UniqueId())
+	if err := d.Set("jobs", result); err != nil {
+		return fmt.Errorf("error setting jobs: %v", err)
+	}
+
+	return nil
+}
+
+func filterJobs(stubs []*api.JobListStub, filter jobsFilter) []*api.JobListStub {
+	match := func(want, got string) bool {
+		return want == "" || want == got
+	}
+
+	ret := make([]*api.JobListStub, 0, len(stubs))
+	for _, j := range stubs {
+		if match(filter.Type, j.Type) &&
+			match(filter.Status, j.Status) &&
+			match(filter.ParentID, j.ParentID) {
+			ret = append(ret, j)
+		}
+	}
+	return ret
+}
+
+func jobStubRaw(j *api.JobListStub) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                 j.ID,
+		"name":               j.Name,
+		"namespace":          j.Namespace,
+		"parent_id":          j.ParentID,
+		"type":               j.Type,
+		"priority":           j.Priority,
+		"status":             j.Status,
+		"status_description": j.StatusDescription,
+		"stop":               j.Stop,
+		"version":            0,
+		"datacenters":        j.Datacenters,
+		"periodic":           j.Periodic,
+		"parameterized":      j.ParameterizedJob,
+		"submit_time":        formatUnixNano(j.SubmitTime),
+		"modify_index":       int(j.ModifyIndex),
+		"job_modify_index":   int(j.JobModifyIndex),
+	}
+}
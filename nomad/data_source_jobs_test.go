@@ -0,0 +1,165 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceJobs_basic(t *testing.T) {
+	dataSourceName := "data.nomad_jobs.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceJobsConfig + `
+data "nomad_jobs" "test" {
+  prefix = "tf-data-jobs-"
+  type   = "batch"
+
+  depends_on = [nomad_job.service, nomad_job.periodic]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.id", "tf-data-jobs-periodic"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.name", "tf-data-jobs-periodic"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.namespace", "default"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.type", "batch"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.priority", "50"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.version", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.periodic", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.parameterized", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.datacenters.0", "dc1"),
+				),
+			},
+			{
+				Config: testAccDataSourceJobsConfig + `
+data "nomad_jobs" "test" {
+  prefix = "tf-data-jobs-"
+
+  depends_on = [nomad_job.service, nomad_job.periodic]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.0.id", "tf-data-jobs-periodic"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.1.id", "tf-data-jobs-service"),
+					resource.TestCheckResourceAttr(dataSourceName, "jobs.1.type", "service"),
+				),
+			},
+		},
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testResourceJob_forceDestroyWithPurge("tf-data-jobs-service", "default"),
+			testResourceJob_forceDestroyWithPurge("tf-data-jobs-periodic", "default"),
+		),
+	})
+}
+
+var testAccDataSourceJobsConfig = `
+resource "nomad_job" "service" {
+  jobspec = <<EOT
+job "tf-data-jobs-service" {
+  datacenters = ["dc1"]
+
+  group "foo" {
+    task "foo" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 32
+      }
+    }
+  }
+}
+EOT
+}
+
+resource "nomad_job" "periodic" {
+  jobspec = <<EOT
+job "tf-data-jobs-periodic" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  periodic {
+    cron = "0 0 1 1 *"
+  }
+
+  group "foo" {
+    task "foo" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/echo"
+        args    = ["test"]
+      }
+    }
+  }
+}
+EOT
+}
+`
+
+func TestFilterJobs(t *testing.T) {
+	stubs := []*api.JobListStub{
+		{ID: "web", Type: "service", Status: "running"},
+		{ID: "report", Type: "batch", Status: "running", Periodic: true},
+		{ID: "report/periodic-1", ParentID: "report", Type: "batch", Status: "dead"},
+		{ID: "agent", Type: "system", Status: "pending"},
+	}
+
+	ids := func(stubs []*api.JobListStub) []string {
+		ret := []string{}
+		for _, j := range stubs {
+			ret = append(ret, j.ID)
+		}
+		return ret
+	}
+
+	cases := []struct {
+		name   string
+		filter jobsFilter
+		want   []string
+	}{
+		{
+			name: "no filter",
+			want: []string{"web", "report", "report/periodic-1", "agent"},
+		},
+		{
+			name:   "type",
+			filter: jobsFilter{Type: "batch"},
+			want:   []string{"report", "report/periodic-1"},
+		},
+		{
+			name:   "status",
+			filter: jobsFilter{Status: "running"},
+			want:   []string{"web", "report"},
+		},
+		{
+			name:   "parent",
+			filter: jobsFilter{ParentID: "report"},
+			want:   []string{"report/periodic-1"},
+		},
+		{
+			name:   "no match",
+			filter: jobsFilter{Type: "system", Status: "running"},
+			want:   []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, ids(filterJobs(stubs, tc.filter)))
+		})
+	}
+}
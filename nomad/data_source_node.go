@@ -0,0 +1,304 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceNode() *schema.Resource {
+	computedList := func(description string, s map[string]*schema.Schema) *schema.Schema {
+		return &schema.Schema{
+			Description: description,
+			Computed:    true,
+			Type:        schema.TypeList,
+			Elem:        &schema.Resource{Schema: s},
+		}
+	}
+	csiPlugins := func(description string) *schema.Schema {
+		return computedList(description, map[string]*schema.Schema{
+			"plugin_id":          computedString(),
+			"alloc_id":           computedString(),
+			"healthy":            computedBool(),
+			"health_description": computedString(),
+		})
+	}
+
+	return &schema.Resource{
+		Read: dataSourceNodeRead,
+
+		Schema: map[string]*schema.Schema{
+			"node_id": {
+				Description:  "ID of the node.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"node_id", "name"},
+			},
+			"name": {
+				Description:  "Exact name of the node.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"node_id", "name"},
+			},
+			"datacenter":             computedString(),
+			"http_addr":              computedString(),
+			"tls_enabled":            computedBool(),
+			"node_class":             computedString(),
+			"status":                 computedString(),
+			"status_description":     computedString(),
+			"drain":                  computedBool(),
+			"scheduling_eligibility": computedString(),
+			"attributes":             computedMap(),
+			"meta":                   computedMap(),
+			"links":                  computedMap(),
+			"drivers": computedList("Task drivers of the node.", map[string]*schema.Schema{
+				"name":               computedString(),
+				"detected":           computedBool(),
+				"healthy":            computedBool(),
+				"health_description": computedString(),
+				"attributes":         computedMap(),
+			}),
+			"host_volumes": computedList("Host volumes of the node.", map[string]*schema.Schema{
+				"name":      computedString(),
+				"path":      computedString(),
+				"read_only": computedBool(),
+			}),
+			"host_networks": computedList("Host networks of the node.", map[string]*schema.Schema{
+				"name":           computedString(),
+				"cidr":           computedString(),
+				"interface":      computedString(),
+				"reserved_ports": computedString(),
+			}),
+			"csi_controller_plugins": csiPlugins("CSI controller plugins running on the node."),
+			"csi_node_plugins":       csiPlugins("CSI node plugins running on the node."),
+			"resources":              nodeResourcesSchema(),
+			"reserved_resources": computedList("Resources of the node reserved for processes outside of Nomad.", map[string]*schema.Schema{
+				"cpu":                 computedInt(),
+				"memory_mb":           computedInt(),
+				"disk_mb":             computedInt(),
+				"reserved_host_ports": computedString(),
+			}),
+			"drain_strategy": computedList("Drain strategy of the node, if it is draining.", map[string]*schema.Schema{
+				"deadline":           computedString(),
+				"ignore_system_jobs": computedBool(),
+				"force_deadline":     computedString(),
+				"started_at":         computedString(),
+			}),
+			"events": computedList("Events of the node.", map[string]*schema.Schema{
+				"message":   computedString(),
+				"subsystem": computedString(),
+				"details":   computedMap(),
+				"timestamp": computedString(),
+			}),
+		},
+	}
+}
+
+// nodeInfo extends api.Node with the host networks of the node, which are
+// returned by Nomad but not exposed by the api package.
+type nodeInfo struct {
+	api.Node
+	HostNetworks map[string]*nodeHostNetwork
+}
+
+type nodeHostNetwork struct {
+	Name          string
+	CIDR          string
+	Interface     string
+	ReservedPorts string
+}
+
+func dataSourceNodeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	nodeID := d.Get("node_id").(string)
+	if nodeID == "" {
+		name := d.Get("name").(string)
+		stubs, _, err := client.Nodes().List(nil)
+		if err != nil {
+			return fmt.Errorf("error listing nodes: %v", err)
+		}
+
+		var ids []string
+		for _, n := range stubs {
+			if n.Name == name {
+				ids = append(ids, n.ID)
+			}
+		}
+		switch len(ids) {
+		case 0:
+			return fmt.Errorf("no node found with name %q", name)
+		case 1:
+			nodeID = ids[0]
+		default:
+			return fmt.Errorf("%d nodes found with name %q, use node_id instead", len(ids), name)
+		}
+	}
+
+	log.Printf("[DEBUG] reading node %q", nodeID)
+	var node nodeInfo
+	if _, err := client.Raw().Query("/v1/node/"+nodeID, &node, nil); err != nil {
+		return fmt.Errorf("error reading node %q: %v", nodeID, err)
+	}
+
+	d.SetId(node.ID)
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("node_id", node.ID)
+	sw.Set("name", node.Name)
+	sw.Set("datacenter", node.Datacenter)
+	sw.Set("http_addr", node.HTTPAddr)
+	sw.Set("tls_enabled", node.TLSEnabled)
+	sw.Set("node_class", node.NodeClass)
+	sw.Set("status", node.Status)
+	sw.Set("status_description", node.StatusDescription)
+	sw.Set("drain", node.Drain)
+	sw.Set("scheduling_eligibility", node.SchedulingEligibility)
+	sw.Set("attributes", node.Attributes)
+	sw.Set("meta", node.Meta)
+	sw.Set("links", node.Links)
+	sw.Set("drivers", nodeDriversRaw(node.Drivers))
+	sw.Set("host_volumes", nodeHostVolumesRaw(node.HostVolumes))
+	sw.Set("host_networks", nodeHostNetworksRaw(node.HostNetworks))
+	sw.Set("csi_controller_plugins", nodeCSIPluginsRaw(node.CSIControllerPlugins))
+	sw.Set("csi_node_plugins", nodeCSIPluginsRaw(node.CSINodePlugins))
+	sw.Set("resources", nodeResourcesRaw(node.NodeResources))
+	sw.Set("reserved_resources", nodeReservedResourcesRaw(node.ReservedResources))
+	sw.Set("drain_strategy", nodeDrainStrategyRaw(node.DrainStrategy))
+	sw.Set("events", nodeEventsRaw(node.Events))
+
+	return sw.Error()
+}
+
+// sortRawByKey sorts a list of flattened objects built from a map by the
+// given string attribute, to keep the output stable.
+func sortRawByKey(list []interface{}, key string) []interface{} {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].(map[string]interface{})[key].(string) < list[j].(map[string]interface{})[key].(string)
+	})
+	return list
+}
+
+func nodeDriversRaw(drivers map[string]*api.DriverInfo) []interface{} {
+	ret := make([]interface{}, 0, len(drivers))
+	for name, info := range drivers {
+		if info == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"name":               name,
+			"detected":           info.Detected,
+			"healthy":            info.Healthy,
+			"health_description": info.HealthDescription,
+			"attributes":         info.Attributes,
+		})
+	}
+	return sortRawByKey(ret, "name")
+}
+
+func nodeHostVolumesRaw(volumes map[string]*api.HostVolumeInfo) []interface{} {
+	ret := make([]interface{}, 0, len(volumes))
+	for name, volume := range volumes {
+		if volume == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"name":      name,
+			"path":      volume.Path,
+			"read_only": volume.ReadOnly,
+		})
+	}
+	return sortRawByKey(ret, "name")
+}
+
+func nodeHostNetworksRaw(networks map[string]*nodeHostNetwork) []interface{} {
+	ret := make([]interface{}, 0, len(networks))
+	for name, network := range networks {
+		if network == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"name":           name,
+			"cidr":           network.CIDR,
+			"interface":      network.Interface,
+			"reserved_ports": network.ReservedPorts,
+		})
+	}
+	return sortRawByKey(ret, "name")
+}
+
+func nodeCSIPluginsRaw(plugins map[string]*api.CSIInfo) []interface{} {
+	ret := make([]interface{}, 0, len(plugins))
+	for id, plugin := range plugins {
+		if plugin == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"plugin_id":          id,
+			"alloc_id":           plugin.AllocID,
+			"healthy":            plugin.Healthy,
+			"health_description": plugin.HealthDescription,
+		})
+	}
+	return sortRawByKey(ret, "plugin_id")
+}
+
+func nodeReservedResourcesRaw(r *api.NodeReservedResources) []interface{} {
+	if r == nil {
+		return nil
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"cpu":                 int(r.Cpu.CpuShares),
+			"memory_mb":           int(r.Memory.MemoryMB),
+			"disk_mb":             int(r.Disk.DiskMB),
+			"reserved_host_ports": r.Networks.ReservedHostPorts,
+		},
+	}
+}
+
+func nodeDrainStrategyRaw(s *api.DrainStrategy) []interface{} {
+	if s == nil {
+		return nil
+	}
+
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"deadline":           s.Deadline.String(),
+			"ignore_system_jobs": s.IgnoreSystemJobs,
+			"force_deadline":     formatTime(s.ForceDeadline),
+			"started_at":         formatTime(s.StartedAt),
+		},
+	}
+}
+
+func nodeEventsRaw(events []*api.NodeEvent) []interface{} {
+	ret := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		if e == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"message":   e.Message,
+			"subsystem": e.Subsystem,
+			"details":   e.Details,
+			"timestamp": e.Timestamp.UTC().Format(time.RFC3339Nano),
+		})
+	}
+	return ret
+}
@@ -0,0 +1,146 @@
+package nomad
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceNode_basic(t *testing.T) {
+	dataSourceName := "data.nomad_node.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "nomad_nodes" "all" {}
+
+data "nomad_node" "test" {
+  node_id = data.nomad_nodes.all.nodes[0].id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "id", "data.nomad_nodes.all", "nodes.0.id"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "name", "data.nomad_nodes.all", "nodes.0.name"),
+					resource.TestCheckResourceAttr(dataSourceName, "datacenter", "dc1"),
+					resource.TestCheckResourceAttr(dataSourceName, "status", "ready"),
+					resource.TestCheckResourceAttr(dataSourceName, "attributes.kernel.name", "linux"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "attributes.cpu.arch"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "drivers.0.name"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "resources.0.memory_mb"),
+					resource.TestCheckResourceAttr(dataSourceName, "reserved_resources.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "drain_strategy.#", "0"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "events.0.message"),
+				),
+			},
+			{
+				Config: `
+data "nomad_nodes" "all" {}
+
+data "nomad_node" "test" {
+  name = data.nomad_nodes.all.nodes[0].name
+}
+`,
+				Check: resource.TestCheckResourceAttrPair(dataSourceName, "node_id", "data.nomad_nodes.all", "nodes.0.id"),
+			},
+			{
+				Config: `
+data "nomad_node" "test" {
+  name = "tf-does-not-exist"
+}
+`,
+				ExpectError: regexp.MustCompile(`no node found with name "tf-does-not-exist"`),
+			},
+		},
+	})
+}
+
+func TestNodeInfo_hostNetworks(t *testing.T) {
+	var node nodeInfo
+	err := json.Unmarshal([]byte(`{
+  "ID": "f7476465-4d6e-c0de-26d0-e383c49be941",
+  "Name": "client-1",
+  "HostNetworks": {
+    "public": {"Name": "public", "CIDR": "10.0.0.0/24", "Interface": "eth0", "ReservedPorts": "22"}
+  }
+}`), &node)
+	require.NoError(t, err)
+	require.Equal(t, "client-1", node.Name)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":           "public",
+			"cidr":           "10.0.0.0/24",
+			"interface":      "eth0",
+			"reserved_ports": "22",
+		},
+	}, nodeHostNetworksRaw(node.HostNetworks))
+}
+
+func TestNodeDriversRaw(t *testing.T) {
+	drivers := map[string]*api.DriverInfo{
+		"raw_exec": {Detected: true, Healthy: true, HealthDescription: "Healthy"},
+		"docker":   {Detected: true, HealthDescription: "Failed to connect to docker daemon"},
+	}
+
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":               "docker",
+			"detected":           true,
+			"healthy":            false,
+			"health_description": "Failed to connect to docker daemon",
+			"attributes":         map[string]string(nil),
+		},
+		map[string]interface{}{
+			"name":               "raw_exec",
+			"detected":           true,
+			"healthy":            true,
+			"health_description": "Healthy",
+			"attributes":         map[string]string(nil),
+		},
+	}, nodeDriversRaw(drivers))
+}
+
+func TestNodeDrainStrategyRaw(t *testing.T) {
+	require.Nil(t, nodeDrainStrategyRaw(nil))
+
+	started := time.Date(2021, 5, 17, 20, 23, 21, 0, time.UTC)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"deadline":           "1h0m0s",
+			"ignore_system_jobs": true,
+			"force_deadline":     "2021-05-17T21:23:21Z",
+			"started_at":         "2021-05-17T20:23:21Z",
+		},
+	}, nodeDrainStrategyRaw(&api.DrainStrategy{
+		DrainSpec: api.DrainSpec{
+			Deadline:         time.Hour,
+			IgnoreSystemJobs: true,
+		},
+		ForceDeadline: started.Add(time.Hour),
+		StartedAt:     started,
+	}))
+}
+
+func TestNodeReservedResourcesRaw(t *testing.T) {
+	require.Nil(t, nodeReservedResourcesRaw(nil))
+
+	r := &api.NodeReservedResources{}
+	r.Cpu.CpuShares = 500
+	r.Memory.MemoryMB = 256
+	r.Networks.ReservedHostPorts = "22,80"
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"cpu":                 500,
+			"memory_mb":           256,
+			"disk_mb":             0,
+			"reserved_host_ports": "22,80",
+		},
+	}, nodeReservedResourcesRaw(r))
+}
@@ -0,0 +1,290 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceNodes() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceNodesRead,
+
+		Schema: map[string]*schema.Schema{
+			"prefix": {
+				Description: "Only return the nodes whose name starts with this prefix.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"datacenter": {
+				Description: "Only return the nodes of this datacenter.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"node_class": {
+				Description: "Only return the nodes of this node class.",
+				Type:        schema.TypeString,
+				Optional:    true,
+			},
+			"status": {
+				Description:  "Only return the nodes with this status.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"initializing", "ready", "down"}, false),
+			},
+			"drain": {
+				Description: "Only return the nodes that are draining, or that are not.",
+				Type:        schema.TypeBool,
+				Optional:    true,
+			},
+			"scheduling_eligibility": {
+				Description:  "Only return the nodes with this scheduling eligibility.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"eligible", "ineligible"}, false),
+			},
+			"attributes": {
+				Description: "Only return the nodes with these attribute values.",
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"nodes": {
+				Description: "The nodes matching the filters.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"name": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"address": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"datacenter": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"node_class": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"version": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"status": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"drain": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"scheduling_eligibility": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"drivers": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"resources": nodeResourcesSchema(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func nodeResourcesSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "The total resources of the node.",
+		Computed:    true,
+		Type:        schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cpu": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"cpu_cores": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"memory_mb": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"disk_mb": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+			},
+		},
+	}
+}
+
+// nodesFilter holds the optional filters of the nomad_nodes data source.
+// Empty values match every node.
+type nodesFilter struct {
+	Prefix                string
+	Datacenter            string
+	NodeClass             string
+	Status                string
+	Drain                 *bool
+	SchedulingEligibility string
+}
+
+func dataSourceNodesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	// Include the resources of the nodes in the response.
+	qOpts := &api.QueryOptions{
+		Params: map[string]string{"resources": "true"},
+	}
+
+	log.Printf("[DEBUG] listing nodes")
+	stubs, _, err := client.Nodes().List(qOpts)
+	if err != nil {
+		return fmt.Errorf("error listing nodes: %v", err)
+	}
+
+	filter := nodesFilter{
+		Prefix:                d.Get("prefix").(string),
+		Datacenter:            d.Get("datacenter").(string),
+		NodeClass:             d.Get("node_class").(string),
+		Status:                d.Get("status").(string),
+		SchedulingEligibility: d.Get("scheduling_eligibility").(string),
+	}
+	if drain, ok := d.GetOkExists("drain"); ok {
+		v := drain.(bool)
+		filter.Drain = &v
+	}
+	stubs = filterNodes(stubs, filter)
+
+	// The attributes are not part of the node stubs, so each remaining node
+	// must be read when filtering on them.
+	attributes := make(map[string]string)
+	for k, v := range d.Get("attributes").(map[string]interface{}) {
+		attributes[k] = v.(string)
+	}
+	if len(attributes) > 0 {
+		matching := make([]*api.NodeListStub, 0, len(stubs))
+		for _, stub := range stubs {
+			node, _, err := client.Nodes().Info(stub.ID, nil)
+			if err != nil {
+				return fmt.Errorf("error reading node %q: %v", stub.ID, err)
+			}
+			if nodeAttributesMatch(node.Attributes, attributes) {
+				matching = append(matching, stub)
+			}
+		}
+		stubs = matching
+	}
+
+	nodes := make([]interface{}, 0, len(stubs))
+	for _, n := range stubs {
+		nodes = append(nodes, nodeStubRaw(n))
+	}
+
+	d.SetId(resource.UniqueId())
+	if err := d.Set("nodes", nodes); err != nil {
+		return fmt.Errorf("error setting nodes: %v", err)
+	}
+
+	return nil
+}
+
+// filterNodes returns the nodes matching the filter, sorted by name to keep
+// the output stable.
+func filterNodes(stubs []*api.NodeListStub, filter nodesFilter) []*api.NodeListStub {
+	match := func(want, got string) bool {
+		return want == "" || want == got
+	}
+
+	ret := make([]*api.NodeListStub, 0, len(stubs))
+	for _, n := range stubs {
+		if strings.HasPrefix(n.Name, filter.Prefix) &&
+			match(filter.Datacenter, n.Datacenter) &&
+			match(filter.NodeClass, n.NodeClass) &&
+			match(filter.Status, n.Status) &&
+			match(filter.SchedulingEligibility, n.SchedulingEligibility) &&
+			(filter.Drain == nil || *filter.Drain == n.Drain) {
+			ret = append(ret, n)
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Name != ret[j].Name {
+			return ret[i].Name < ret[j].Name
+		}
+		return ret[i].ID < ret[j].ID
+	})
+	return ret
+}
+
+func nodeAttributesMatch(attributes, want map[string]string) bool {
+	for k, v := range want {
+		if got, ok := attributes[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+func nodeStubRaw(n *api.NodeListStub) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                     n.ID,
+		"name":                   n.Name,
+		"address":                n.Address,
+		"datacenter":             n.Datacenter,
+		"node_class":             n.NodeClass,
+		"version":                n.Version,
+		"status":                 n.Status,
+		"drain":                  n.Drain,
+		"scheduling_eligibility": n.SchedulingEligibility,
+		"drivers":                nodeDriverNames(n.Drivers),
+		"resources":              nodeResourcesRaw(n.NodeResources),
+	}
+}
+
+// nodeDriverNames returns the sorted names of the drivers detected on a node.
+func nodeDriverNames(drivers map[string]*api.DriverInfo) []string {
+	names := make([]string, 0, len(drivers))
+	for name, info := range drivers {
+		if info != nil && info.Detected {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func nodeResourcesRaw(r *api.NodeResources) []interface{} {
+	if r == nil {
+		return nil
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"cpu":       int(r.Cpu.CpuShares),
+			"cpu_cores": int(r.Cpu.TotalCpuCores),
+			"memory_mb": int(r.Memory.MemoryMB),
+			"disk_mb":   int(r.Disk.DiskMB),
+		},
+	}
+}
@@ -0,0 +1,141 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceNodes_basic(t *testing.T) {
+	dataSourceName := "data.nomad_nodes.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "nomad_nodes" "test" {
+  datacenter = "dc1"
+  status     = "ready"
+
+  attributes = {
+    "kernel.name" = "linux"
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "nodes.#", "1"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "nodes.0.id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "nodes.0.name"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "nodes.0.address"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "nodes.0.version"),
+					resource.TestCheckResourceAttr(dataSourceName, "nodes.0.datacenter", "dc1"),
+					resource.TestCheckResourceAttr(dataSourceName, "nodes.0.drain", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "nodes.0.scheduling_eligibility", "eligible"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "nodes.0.drivers.0"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "nodes.0.resources.0.cpu"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "nodes.0.resources.0.memory_mb"),
+				),
+			},
+			{
+				Config: `
+data "nomad_nodes" "test" {
+  attributes = {
+    "kernel.name" = "plan9"
+  }
+}
+`,
+				Check: resource.TestCheckResourceAttr(dataSourceName, "nodes.#", "0"),
+			},
+		},
+	})
+}
+
+func TestFilterNodes(t *testing.T) {
+	yes, no := true, false
+	nodes := []*api.NodeListStub{
+		{ID: "3", Name: "web-2", Datacenter: "dc1", NodeClass: "web", Status: "ready", SchedulingEligibility: "eligible"},
+		{ID: "1", Name: "web-1", Datacenter: "dc1", NodeClass: "web", Status: "ready", SchedulingEligibility: "ineligible", Drain: true},
+		{ID: "2", Name: "db-1", Datacenter: "dc2", NodeClass: "db", Status: "down", SchedulingEligibility: "eligible"},
+	}
+
+	ids := func(stubs []*api.NodeListStub) []string {
+		ret := []string{}
+		for _, n := range stubs {
+			ret = append(ret, n.ID)
+		}
+		return ret
+	}
+
+	cases := []struct {
+		name   string
+		filter nodesFilter
+		want   []string
+	}{
+		{
+			name: "no filter",
+			want: []string{"2", "1", "3"},
+		},
+		{
+			name:   "prefix",
+			filter: nodesFilter{Prefix: "web-"},
+			want:   []string{"1", "3"},
+		},
+		{
+			name:   "datacenter and class",
+			filter: nodesFilter{Datacenter: "dc1", NodeClass: "web"},
+			want:   []string{"1", "3"},
+		},
+		{
+			name:   "status",
+			filter: nodesFilter{Status: "down"},
+			want:   []string{"2"},
+		},
+		{
+			name:   "draining",
+			filter: nodesFilter{Drain: &yes},
+			want:   []string{"1"},
+		},
+		{
+			name:   "not draining and eligible",
+			filter: nodesFilter{Drain: &no, SchedulingEligibility: "eligible"},
+			want:   []string{"2", "3"},
+		},
+		{
+			name:   "no match",
+			filter: nodesFilter{Datacenter: "dc3"},
+			want:   []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, ids(filterNodes(nodes, tc.filter)))
+		})
+	}
+}
+
+func TestNodeAttributesMatch(t *testing.T) {
+	attributes := map[string]string{
+		"kernel.name": "linux",
+		"cpu.arch":    "amd64",
+	}
+
+	require.True(t, nodeAttributesMatch(attributes, nil))
+	require.True(t, nodeAttributesMatch(attributes, map[string]string{"kernel.name": "linux"}))
+	require.True(t, nodeAttributesMatch(attributes, map[string]string{"kernel.name": "linux", "cpu.arch": "amd64"}))
+	require.False(t, nodeAttributesMatch(attributes, map[string]string{"kernel.name": "darwin"}))
+	require.False(t, nodeAttributesMatch(attributes, map[string]string{"os.name": "ubuntu"}))
+}
+
+func TestNodeDriverNames(t *testing.T) {
+	drivers := map[string]*api.DriverInfo{
+		"raw_exec": {Detected: true, Healthy: true},
+		"docker":   {Detected: true, Healthy: false},
+		"qemu":     {Detected: false},
+	}
+	require.Equal(t, []string{"docker", "raw_exec"}, nodeDriverNames(drivers))
+}
@@ -0,0 +1,93 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceRaftConfiguration() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceRaftConfigurationRead,
+
+		Schema: map[string]*schema.Schema{
+			"index": {
+				Description: "Raft index of the configuration.",
+				Computed:    true,
+				Type:        schema.TypeInt,
+			},
+			"servers": {
+				Description: "The servers of the Raft peer set.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"node": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"address": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"leader": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"voter": {
+							Computed: true,
+							Type:     schema.TypeBool,
+						},
+						"raft_protocol": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRaftConfigurationRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	log.Printf("[DEBUG] Reading Raft configuration")
+	cfg, err := client.Operator().RaftGetConfiguration(nil)
+	if err != nil {
+		return fmt.Errorf("error reading Raft configuration: %v", err)
+	}
+	log.Printf("[DEBUG] Read Raft configuration with %d servers", len(cfg.Servers))
+
+	d.SetId(client.Address() + "/operator/raft/configuration")
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("index", int(cfg.Index))
+	sw.Set("servers", raftServersRaw(cfg.Servers))
+	return sw.Error()
+}
+
+func raftServersRaw(servers []*api.RaftServer) []interface{} {
+	ret := make([]interface{}, 0, len(servers))
+	for _, s := range servers {
+		if s == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"id":            s.ID,
+			"node":          s.Node,
+			"address":       s.Address,
+			"leader":        s.Leader,
+			"voter":         s.Voter,
+			"raft_protocol": s.RaftProtocol,
+		})
+	}
+	return ret
+}
@@ -0,0 +1,70 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceRaftConfiguration_basic(t *testing.T) {
+	dataSourceName := "data.nomad_raft_configuration.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `data "nomad_raft_configuration" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "index"),
+					resource.TestCheckResourceAttr(dataSourceName, "servers.#", "1"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "servers.0.id"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "servers.0.address"),
+					resource.TestCheckResourceAttr(dataSourceName, "servers.0.leader", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "servers.0.voter", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestRaftServersRaw(t *testing.T) {
+	servers := []*api.RaftServer{
+		{
+			ID:           "a7f8b0f6-4d9e-4f0b-8b3a-2c8f2a1f7e01",
+			Node:         "server-1.global",
+			Address:      "10.0.0.1:4647",
+			Leader:       true,
+			Voter:        true,
+			RaftProtocol: "3",
+		},
+		nil,
+		{
+			ID:           "c1d2e3f4-0000-4f0b-8b3a-2c8f2a1f7e02",
+			Node:         "server-2.global",
+			Address:      "10.0.0.2:4647",
+			RaftProtocol: "3",
+		},
+	}
+
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"id":            "a7f8b0f6-4d9e-4f0b-8b3a-2c8f2a1f7e01",
+			"node":          "server-1.global",
+			"address":       "10.0.0.1:4647",
+			"leader":        true,
+			"voter":         true,
+			"raft_protocol": "3",
+		},
+		map[string]interface{}{
+			"id":            "c1d2e3f4-0000-4f0b-8b3a-2c8f2a1f7e02",
+			"node":          "server-2.global",
+			"address":       "10.0.0.2:4647",
+			"leader":        false,
+			"voter":         false,
+			"raft_protocol": "3",
+		},
+	}, raftServersRaw(servers))
+}
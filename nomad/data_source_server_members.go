@@ -0,0 +1,118 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"sort"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-provider-nomad/nomad/helper"
+)
+
+func dataSourceServerMembers() *schema.Resource {
+	memberSchema := agentMemberSchema()
+	memberSchema["leader"] = &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeBool,
+	}
+
+	return &schema.Resource{
+		Read: dataSourceServerMembersRead,
+
+		Schema: map[string]*schema.Schema{
+			"server_name": {
+				Description: "Name of the server that answered the request.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"server_region": {
+				Description: "Region of the server that answered the request.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"server_datacenter": {
+				Description: "Datacenter of the server that answered the request.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+			"members": {
+				Description: "The servers known to the cluster, sorted by region, datacenter and name.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: memberSchema,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceServerMembersRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	log.Printf("[DEBUG] Reading server members")
+	resp, err := client.Agent().Members()
+	if err != nil {
+		return fmt.Errorf("error reading server members: %v", err)
+	}
+	log.Printf("[DEBUG] Read %d server members", len(resp.Members))
+
+	leaders, err := serverRegionLeaders(client.Status(), resp.ServerRegion, resp.Members)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(client.Address() + "/agent/members")
+
+	sw := helper.NewStateWriter(d)
+	sw.Set("server_name", resp.ServerName)
+	sw.Set("server_region", resp.ServerRegion)
+	sw.Set("server_datacenter", resp.ServerDC)
+	sw.Set("members", serverMembersRaw(resp.Members, leaders))
+	return sw.Error()
+}
+
+// serverRegionLeaders returns the RPC address of the leader of each region
+// the members belong to. The leader of the local region must be known, but
+// other regions may be unreachable so their errors are only logged.
+func serverRegionLeaders(status *api.Status, localRegion string, members []*api.AgentMember) (map[string]string, error) {
+	leaders := make(map[string]string)
+
+	leader, err := status.Leader()
+	if err != nil {
+		return nil, fmt.Errorf("error reading the leader of region %q: %v", localRegion, err)
+	}
+	leaders[localRegion] = leader
+
+	for _, m := range members {
+		region := m.Tags["region"]
+		if _, ok := leaders[region]; ok {
+			continue
+		}
+
+		leader, err := status.RegionLeader(region)
+		if err != nil {
+			log.Printf("[WARN] error reading the leader of region %q: %v", region, err)
+		}
+		leaders[region] = leader
+	}
+
+	return leaders, nil
+}
+
+func serverMembersRaw(members []*api.AgentMember, leaders map[string]string) []interface{} {
+	sorted := make([]*api.AgentMember, len(members))
+	copy(sorted, members)
+	sort.Sort(api.AgentMembersNameSort(sorted))
+
+	ret := make([]interface{}, 0, len(sorted))
+	for _, m := range sorted {
+		raw := agentMemberRaw(m)
+		addr := net.JoinHostPort(m.Addr, m.Tags["port"])
+		raw["leader"] = leaders[m.Tags["region"]] == addr
+		ret = append(ret, raw)
+	}
+	return ret
+}
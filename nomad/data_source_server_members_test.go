@@ -0,0 +1,77 @@
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccDataSourceServerMembers_basic(t *testing.T) {
+	dataSourceName := "data.nomad_server_members.test"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: `data "nomad_server_members" "test" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "server_region", "global"),
+					resource.TestCheckResourceAttr(dataSourceName, "server_datacenter", "dc1"),
+					resource.TestCheckResourceAttr(dataSourceName, "members.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "members.0.status", "alive"),
+					resource.TestCheckResourceAttr(dataSourceName, "members.0.leader", "true"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "members.0.build"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "members.0.raft_version"),
+				),
+			},
+		},
+	})
+}
+
+func TestServerMembersRaw(t *testing.T) {
+	member := func(name, addr, region, dc string) *api.AgentMember {
+		return &api.AgentMember{
+			Name:   name,
+			Addr:   addr,
+			Port:   4648,
+			Status: "alive",
+			Tags: map[string]string{
+				"region": region,
+				"dc":     dc,
+				"port":   "4647",
+			},
+		}
+	}
+
+	members := []*api.AgentMember{
+		member("c.global", "10.0.0.3", "global", "dc2"),
+		member("b.global", "10.0.0.2", "global", "dc1"),
+		member("a.europe", "10.1.0.1", "europe", "dc1"),
+		member("a.global", "10.0.0.1", "global", "dc1"),
+	}
+	leaders := map[string]string{
+		"global": "10.0.0.2:4647",
+		"europe": "",
+	}
+
+	raw := serverMembersRaw(members, leaders)
+	require.Len(t, raw, 4)
+
+	names := []string{}
+	leaderNames := []string{}
+	for _, r := range raw {
+		m := r.(map[string]interface{})
+		names = append(names, m["name"].(string))
+		if m["leader"].(bool) {
+			leaderNames = append(leaderNames, m["name"].(string))
+		}
+	}
+	require.Equal(t, []string{"a.europe", "a.global", "b.global", "c.global"}, names)
+	require.Equal(t, []string{"b.global"}, leaderNames)
+
+	// The members given are left untouched.
+	require.Equal(t, "c.global", members[0].Name)
+}
@@ -1,9 +1,11 @@
 package nomad
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -25,6 +27,12 @@ func dataSourceJob() *schema.Resource {
 				Optional:    true,
 				Default:     "default",
 			},
+			"include_json": {
+				Description: "Include Canonical JSON",
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+			},
 			// computed attributes
 			"name": {
 				Description: "Job Name",
@@ -97,7 +105,7 @@ func dataSourceJob() *schema.Resource {
 				Computed:    true,
 				Type:        schema.TypeString,
 			},
-			"task_groups": taskGroupSchema(),
+			"task_groups": jobTaskGroupsDetailSchema(),
 			"stable": {
 				Description: "Job Stable",
 				Type:        schema.TypeBool,
@@ -158,6 +166,92 @@ func dataSourceJob() *schema.Resource {
 					},
 				},
 			},
+			"meta": {
+				Description: "Job Meta",
+				Computed:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"parameterized_job": {
+				Description: "Job Parameterized Configuration",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"payload": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"meta_required": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"meta_optional": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"update_strategy": updateStrategySchema(),
+			"multiregion": {
+				Description: "Job Multiregion Configuration",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"strategy": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"max_parallel": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"on_failure": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"regions": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"count": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"datacenters": {
+										Type:     schema.TypeList,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+									"meta": {
+										Type:     schema.TypeMap,
+										Computed: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"json": {
+				Description: "Job Canonical JSON",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
 		},
 	}
 }
@@ -201,7 +295,7 @@ func dataSourceJobRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("stop", job.Stop)
 	d.Set("priority", job.Priority)
 	d.Set("parent_id", job.ParentID)
-	d.Set("task_groups", jobTaskGroupsRaw(job.TaskGroups))
+	d.Set("task_groups", jobTaskGroupsDetailRaw(job.TaskGroups))
 	d.Set("stable", job.Stable)
 	d.Set("all_at_once", job.AllAtOnce)
 	d.Set("constraints", job.Constraints)
@@ -224,6 +318,425 @@ func dataSourceJobRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		d.Set("periodic_config", []map[string]interface{}{periodic})
 	}
+	d.Set("meta", job.Meta)
+	if job.ParameterizedJob != nil {
+		d.Set("parameterized_job", []map[string]interface{}{
+			{
+				"payload":       job.ParameterizedJob.Payload,
+				"meta_required": job.ParameterizedJob.MetaRequired,
+				"meta_optional": job.ParameterizedJob.MetaOptional,
+			},
+		})
+	}
+	d.Set("update_strategy", updateStrategyRaw(job.Update))
+	d.Set("multiregion", multiregionRaw(job.Multiregion))
+
+	jobJSON := ""
+	if d.Get("include_json").(bool) {
+		b, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("error encoding job %q: %v", id, err)
+		}
+		jobJSON = string(b)
+	}
+	d.Set("json", jobJSON)
 
 	return nil
 }
+
+// jobTaskGroupsDetailSchema extends the task groups exposed by the nomad_job
+// resource with their networks, services and update strategy, and with the
+// resources and services of their tasks.
+func jobTaskGroupsDetailSchema() *schema.Schema {
+	s := taskGroupSchema()
+
+	tgSchema := s.Elem.(*schema.Resource).Schema
+	tgSchema["networks"] = networksSchema()
+	tgSchema["services"] = servicesSchema()
+	tgSchema["update_strategy"] = updateStrategySchema()
+
+	taskSchema := tgSchema["task"].Elem.(*schema.Resource).Schema
+	taskSchema["services"] = servicesSchema()
+	taskSchema["resources"] = &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"cpu": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"cores": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"memory_mb": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"memory_max_mb": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"disk_mb": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"networks": networksSchema(),
+			},
+		},
+	}
+
+	return s
+}
+
+func networksSchema() *schema.Schema {
+	port := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"label": {
+				Computed: true,
+				Type:     schema.TypeString,
+			},
+			"value": {
+				Computed: true,
+				Type:     schema.TypeInt,
+			},
+			"to": {
+				Computed: true,
+				Type:     schema.TypeInt,
+			},
+			"host_network": {
+				Computed: true,
+				Type:     schema.TypeString,
+			},
+		},
+	}
+
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"mode": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"mbits": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"reserved_ports": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem:     port,
+				},
+				"dynamic_ports": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem:     port,
+				},
+			},
+		},
+	}
+}
+
+func servicesSchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"port_label": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"address_mode": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"tags": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"canary_tags": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"meta": {
+					Computed: true,
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"connect": {
+					Computed: true,
+					Type:     schema.TypeBool,
+				},
+				"checks": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"name": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"type": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"path": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"port_label": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"interval": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+							"timeout": {
+								Computed: true,
+								Type:     schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func updateStrategySchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"stagger": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"max_parallel": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"health_check": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"min_healthy_time": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"healthy_deadline": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"progress_deadline": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"canary": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"auto_revert": {
+					Computed: true,
+					Type:     schema.TypeBool,
+				},
+				"auto_promote": {
+					Computed: true,
+					Type:     schema.TypeBool,
+				},
+			},
+		},
+	}
+}
+
+// jobTaskGroupsDetailRaw flattens the task groups like jobTaskGroupsRaw and
+// adds the attributes of jobTaskGroupsDetailSchema.
+func jobTaskGroupsDetailRaw(tgs []*api.TaskGroup) []interface{} {
+	ret := jobTaskGroupsRaw(tgs)
+
+	for i, tg := range tgs {
+		tgM := ret[i].(map[string]interface{})
+		tgM["networks"] = networksRaw(tg.Networks)
+		tgM["services"] = servicesRaw(tg.Services)
+		tgM["update_strategy"] = updateStrategyRaw(tg.Update)
+
+		tasksI := tgM["task"].([]interface{})
+		for j, task := range tg.Tasks {
+			taskM := tasksI[j].(map[string]interface{})
+			taskM["services"] = servicesRaw(task.Services)
+			taskM["resources"] = taskResourcesRaw(task.Resources)
+		}
+	}
+
+	return ret
+}
+
+func taskResourcesRaw(r *api.Resources) []interface{} {
+	if r == nil {
+		return nil
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"cpu":           intValue(r.CPU),
+			"cores":         intValue(r.Cores),
+			"memory_mb":     intValue(r.MemoryMB),
+			"memory_max_mb": intValue(r.MemoryMaxMB),
+			"disk_mb":       intValue(r.DiskMB),
+			"networks":      networksRaw(r.Networks),
+		},
+	}
+}
+
+func networksRaw(networks []*api.NetworkResource) []interface{} {
+	portsRaw := func(ports []api.Port) []interface{} {
+		ret := make([]interface{}, 0, len(ports))
+		for _, p := range ports {
+			ret = append(ret, map[string]interface{}{
+				"label":        p.Label,
+				"value":        p.Value,
+				"to":           p.To,
+				"host_network": p.HostNetwork,
+			})
+		}
+		return ret
+	}
+
+	ret := make([]interface{}, 0, len(networks))
+	for _, n := range networks {
+		if n == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"mode":           n.Mode,
+			"mbits":          intValue(n.MBits),
+			"reserved_ports": portsRaw(n.ReservedPorts),
+			"dynamic_ports":  portsRaw(n.DynamicPorts),
+		})
+	}
+	return ret
+}
+
+func servicesRaw(services []*api.Service) []interface{} {
+	ret := make([]interface{}, 0, len(services))
+	for _, s := range services {
+		if s == nil {
+			continue
+		}
+
+		checks := make([]interface{}, 0, len(s.Checks))
+		for _, c := range s.Checks {
+			checks = append(checks, map[string]interface{}{
+				"name":       c.Name,
+				"type":       c.Type,
+				"path":       c.Path,
+				"port_label": c.PortLabel,
+				"interval":   c.Interval.String(),
+				"timeout":    c.Timeout.String(),
+			})
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"name":         s.Name,
+			"port_label":   s.PortLabel,
+			"address_mode": s.AddressMode,
+			"tags":         s.Tags,
+			"canary_tags":  s.CanaryTags,
+			"meta":         s.Meta,
+			"connect":      s.Connect != nil,
+			"checks":       checks,
+		})
+	}
+	return ret
+}
+
+func updateStrategyRaw(u *api.UpdateStrategy) []interface{} {
+	if u == nil {
+		return nil
+	}
+
+	duration := func(d *time.Duration) string {
+		if d == nil {
+			return ""
+		}
+		return d.String()
+	}
+
+	healthCheck := ""
+	if u.HealthCheck != nil {
+		healthCheck = *u.HealthCheck
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"stagger":           duration(u.Stagger),
+			"max_parallel":      intValue(u.MaxParallel),
+			"health_check":      healthCheck,
+			"min_healthy_time":  duration(u.MinHealthyTime),
+			"healthy_deadline":  duration(u.HealthyDeadline),
+			"progress_deadline": duration(u.ProgressDeadline),
+			"canary":            intValue(u.Canary),
+			"auto_revert":       u.AutoRevert != nil && *u.AutoRevert,
+			"auto_promote":      u.AutoPromote != nil && *u.AutoPromote,
+		},
+	}
+}
+
+func multiregionRaw(m *api.Multiregion) []interface{} {
+	if m == nil {
+		return nil
+	}
+
+	var strategy []interface{}
+	if m.Strategy != nil {
+		onFailure := ""
+		if m.Strategy.OnFailure != nil {
+			onFailure = *m.Strategy.OnFailure
+		}
+		strategy = []interface{}{
+			map[string]interface{}{
+				"max_parallel": intValue(m.Strategy.MaxParallel),
+				"on_failure":   onFailure,
+			},
+		}
+	}
+
+	regions := make([]interface{}, 0, len(m.Regions))
+	for _, r := range m.Regions {
+		if r == nil {
+			continue
+		}
+		regions = append(regions, map[string]interface{}{
+			"name":        r.Name,
+			"count":       intValue(r.Count),
+			"datacenters": r.Datacenters,
+			"meta":        r.Meta,
+		})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"strategy": strategy,
+			"regions":  regions,
+		},
+	}
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
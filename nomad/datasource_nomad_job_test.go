@@ -3,8 +3,11 @@ package nomad
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
@@ -29,6 +32,53 @@ func TestAccDataSourceNomadJob_Basic(t *testing.T) {
 						"data.nomad_job.test-job", "priority", "50"),
 					resource.TestCheckResourceAttr(
 						"data.nomad_job.test-job", "namespace", "default"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "update_strategy.0.max_parallel", "2"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "update_strategy.0.min_healthy_time", "11s"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "update_strategy.0.canary", "1"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "task_groups.0.update_strategy.0.progress_deadline", "11m0s"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "task_groups.0.task.0.resources.0.cpu", "100"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "task_groups.0.task.0.resources.0.memory_mb", "10"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job", "json", ""),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceNomadJob_Parameterized(t *testing.T) {
+	job := "testjobds_parameterized"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testProviders,
+		CheckDestroy: testResourceJob_forceDestroyWithPurge(job, "default"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccJobDataSourceConfigParameterized(job),
+				Check: resource.ComposeTestCheckFunc(
+					testAccDataSourceNomadJobExists("data.nomad_job.test-job-parameterized", "default"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job-parameterized", "meta.owner", "team-a"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job-parameterized", "parameterized_job.0.payload", "required"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job-parameterized", "parameterized_job.0.meta_required.0", "target"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job-parameterized", "task_groups.0.networks.0.dynamic_ports.0.label", "http"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job-parameterized", "task_groups.0.services.0.name", "foo-http"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job-parameterized", "task_groups.0.services.0.port_label", "http"),
+					resource.TestCheckResourceAttr(
+						"data.nomad_job.test-job-parameterized", "task_groups.0.services.0.checks.0.interval", "10s"),
+					resource.TestCheckResourceAttrSet(
+						"data.nomad_job.test-job-parameterized", "json"),
 				),
 			},
 		},
@@ -247,3 +297,185 @@ data "nomad_job" "test-job-periodic" {
 }
 `
 }
+
+func testAccJobDataSourceConfigParameterized(job string) string {
+	return `
+resource "nomad_job" "job-instance-parameterized" {
+	jobspec = <<EOT
+		job "` + job + `" {
+			datacenters = ["dc1"]
+			type = "batch"
+			meta {
+				owner = "team-a"
+			}
+			parameterized {
+				payload       = "required"
+				meta_required = ["target"]
+			}
+			group "foo" {
+				network {
+					port "http" {}
+				}
+				service {
+					name = "foo-http"
+					port = "http"
+					check {
+						type     = "http"
+						path     = "/health"
+						interval = "10s"
+						timeout  = "2s"
+					}
+				}
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/echo"
+						args = ["test"]
+					}
+
+					resources {
+						cpu = 100
+						memory = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+
+data "nomad_job" "test-job-parameterized" {
+  job_id       = "${nomad_job.job-instance-parameterized.id}"
+  include_json = true
+}
+`
+}
+
+func TestJobTaskGroupsDetailRaw(t *testing.T) {
+	tgs := []*api.TaskGroup{
+		{
+			Name: helper.StringToPtr("web"),
+			Networks: []*api.NetworkResource{
+				{
+					Mode:         "bridge",
+					DynamicPorts: []api.Port{{Label: "http", To: 8080}},
+				},
+			},
+			Services: []*api.Service{
+				{
+					Name:      "web",
+					PortLabel: "http",
+					Tags:      []string{"v1"},
+					Checks: []api.ServiceCheck{
+						{Type: "http", Path: "/health", Interval: 10 * time.Second, Timeout: 2 * time.Second},
+					},
+				},
+			},
+			Update: &api.UpdateStrategy{
+				MaxParallel: helper.IntToPtr(1),
+				AutoRevert:  helper.BoolToPtr(true),
+			},
+			Tasks: []*api.Task{
+				{
+					Name:   "server",
+					Driver: "docker",
+					Resources: &api.Resources{
+						CPU:      helper.IntToPtr(500),
+						MemoryMB: helper.IntToPtr(256),
+					},
+				},
+			},
+		},
+	}
+
+	raw := jobTaskGroupsDetailRaw(tgs)
+	require.Len(t, raw, 1)
+	tg := raw[0].(map[string]interface{})
+
+	require.Equal(t, "web", tg["name"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"mode":           "bridge",
+			"mbits":          0,
+			"reserved_ports": []interface{}{},
+			"dynamic_ports": []interface{}{
+				map[string]interface{}{"label": "http", "value": 0, "to": 8080, "host_network": ""},
+			},
+		},
+	}, tg["networks"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"name":         "web",
+			"port_label":   "http",
+			"address_mode": "",
+			"tags":         []string{"v1"},
+			"canary_tags":  []string(nil),
+			"meta":         map[string]string(nil),
+			"connect":      false,
+			"checks": []interface{}{
+				map[string]interface{}{
+					"name":       "",
+					"type":       "http",
+					"path":       "/health",
+					"port_label": "",
+					"interval":   "10s",
+					"timeout":    "2s",
+				},
+			},
+		},
+	}, tg["services"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"stagger":           "",
+			"max_parallel":      1,
+			"health_check":      "",
+			"min_healthy_time":  "",
+			"healthy_deadline":  "",
+			"progress_deadline": "",
+			"canary":            0,
+			"auto_revert":       true,
+			"auto_promote":      false,
+		},
+	}, tg["update_strategy"])
+
+	task := tg["task"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "server", task["name"])
+	require.Equal(t, []interface{}{}, task["services"])
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"cpu":           500,
+			"cores":         0,
+			"memory_mb":     256,
+			"memory_max_mb": 0,
+			"disk_mb":       0,
+			"networks":      []interface{}{},
+		},
+	}, task["resources"])
+}
+
+func TestMultiregionRaw(t *testing.T) {
+	require.Nil(t, multiregionRaw(nil))
+
+	require.Equal(t, []interface{}{
+		map[string]interface{}{
+			"strategy": []interface{}{
+				map[string]interface{}{"max_parallel": 1, "on_failure": "fail_all"},
+			},
+			"regions": []interface{}{
+				map[string]interface{}{
+					"name":        "east",
+					"count":       2,
+					"datacenters": []string{"east-1"},
+					"meta":        map[string]string(nil),
+				},
+			},
+		},
+	}, multiregionRaw(&api.Multiregion{
+		Strategy: &api.MultiregionStrategy{
+			MaxParallel: helper.IntToPtr(1),
+			OnFailure:   helper.StringToPtr("fail_all"),
+		},
+		Regions: []*api.MultiregionRegion{
+			{Name: "east", Count: helper.IntToPtr(2), Datacenters: []string{"east-1"}},
+		},
+	}))
+}
@@ -0,0 +1,436 @@
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+)
+
+// eventMonitorRefreshInterval is how often a waiter reads the object it is
+// watching from the API in case an event was missed.
+const eventMonitorRefreshInterval = 30 * time.Second
+
+// eventMonitorIdleTimeout is how long the event stream is kept open once it
+// has no subscribers, so following an evaluation and then its deployment
+// doesn't reconnect in between.
+const eventMonitorIdleTimeout = 10 * time.Second
+
+// eventMonitorRetryMin and eventMonitorRetryMax bound the delay before
+// connecting to the event stream of a namespace again after a failure.
+const (
+	eventMonitorRetryMin = 5 * time.Second
+	eventMonitorRetryMax = 5 * time.Minute
+)
+
+// eventStreamError is returned when the event stream can't be used, in which
+// case callers should fall back to polling.
+type eventStreamError struct {
+	err error
+}
+
+func (e *eventStreamError) Error() string {
+	return fmt.Sprintf("event stream unavailable: %v", e.err)
+}
+
+// eventMonitor shares subscriptions to the Nomad event stream between all the
+// resources of the provider waiting on evaluations and deployments.
+//
+// A stream is opened per namespace, so tokens only allowed to read some
+// namespaces can use it, on first use and closed once it has had no
+// subscribers for idleTimeout. If the cluster doesn't support event
+// streaming, or the token is not allowed to use it, the error is returned to
+// the subscribers so they can fall back to polling, and the stream is only
+// requested again after a backoff.
+type eventMonitor struct {
+	client      *api.Client
+	idleTimeout time.Duration
+
+	lock    sync.Mutex
+	streams map[string]*eventStream
+}
+
+// eventStream is the subscription to the events of a namespace.
+type eventStream struct {
+	namespace string
+	running   bool
+	stopped   chan struct{}
+	cancel    context.CancelFunc
+	idleTimer *time.Timer
+	subs      map[*eventSubscription]struct{}
+
+	// failures counts the consecutive failed attempts to open the stream,
+	// the next attempt is not made before retryAt.
+	failures int
+	retryAt  time.Time
+	lastErr  error
+}
+
+// eventSubscription receives the events of a topic that match a key. Only the
+// latest event is kept if the subscriber falls behind.
+type eventSubscription struct {
+	stream *eventStream
+	topic  api.Topic
+	key    string
+	events chan *api.Event
+
+	// done is closed when the event stream stops.
+	done <-chan struct{}
+}
+
+func newEventMonitor(client *api.Client) *eventMonitor {
+	return &eventMonitor{
+		client:      client,
+		idleTimeout: eventMonitorIdleTimeout,
+		streams:     make(map[string]*eventStream),
+	}
+}
+
+// subscribe returns a subscription for the events of topic in namespace where
+// either the event key or one of its filter keys matches key.
+func (m *eventMonitor) subscribe(namespace string, topic api.Topic, key string) (*eventSubscription, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if namespace == "" {
+		namespace = "default"
+	}
+	s, ok := m.streams[namespace]
+	if !ok {
+		s = &eventStream{
+			namespace: namespace,
+			subs:      make(map[*eventSubscription]struct{}),
+		}
+		m.streams[namespace] = s
+	}
+
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+		s.idleTimer = nil
+	}
+
+	if !s.running {
+		if time.Now().Before(s.retryAt) {
+			return nil, &eventStreamError{err: s.lastErr}
+		}
+		if err := m.startLocked(s); err != nil {
+			s.failures++
+			s.lastErr = err
+			s.retryAt = time.Now().Add(eventMonitorRetryDelay(s.failures))
+			return nil, &eventStreamError{err: err}
+		}
+		s.failures = 0
+		s.lastErr = nil
+	}
+
+	sub := &eventSubscription{
+		stream: s,
+		topic:  topic,
+		key:    key,
+		events: make(chan *api.Event, 1),
+		done:   s.stopped,
+	}
+	s.subs[sub] = struct{}{}
+
+	return sub, nil
+}
+
+// eventMonitorRetryDelay returns the delay before opening a stream again after
+// it failed failures times in a row.
+func eventMonitorRetryDelay(failures int) time.Duration {
+	delay := eventMonitorRetryMin
+	for i := 1; i < failures && delay < eventMonitorRetryMax; i++ {
+		delay *= 2
+	}
+	if delay > eventMonitorRetryMax {
+		delay = eventMonitorRetryMax
+	}
+	return delay
+}
+
+func (m *eventMonitor) unsubscribe(sub *eventSubscription) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s := sub.stream
+	delete(s.subs, sub)
+
+	if len(s.subs) == 0 && s.running && s.idleTimer == nil {
+		s.idleTimer = time.AfterFunc(m.idleTimeout, func() { m.stopIfIdle(s) })
+	}
+}
+
+// stopIfIdle closes the event stream if it still has no subscribers.
+func (m *eventMonitor) stopIfIdle(s *eventStream) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	s.idleTimer = nil
+	if len(s.subs) > 0 || !s.running {
+		return
+	}
+
+	log.Printf("[DEBUG] closing the idle Nomad event stream of namespace %q", s.namespace)
+	s.cancel()
+	s.running = false
+}
+
+func (m *eventMonitor) startLocked(s *eventStream) error {
+	topics := map[api.Topic][]string{
+		api.TopicEvaluation: {"*"},
+		api.TopicDeployment: {"*"},
+		api.TopicAllocation: {"*"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	eventsCh, err := m.client.EventStream().Stream(ctx, topics, 0, &api.QueryOptions{
+		Namespace: s.namespace,
+	})
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	log.Printf("[DEBUG] subscribed to the Nomad event stream of namespace %q", s.namespace)
+	s.running = true
+	s.stopped = make(chan struct{})
+	s.cancel = cancel
+	go m.run(ctx, s, eventsCh, cancel, s.stopped)
+
+	return nil
+}
+
+func (m *eventMonitor) run(ctx context.Context, s *eventStream, eventsCh <-chan *api.Events, cancel context.CancelFunc, stopped chan struct{}) {
+	defer func() {
+		cancel()
+
+		m.lock.Lock()
+		defer m.lock.Unlock()
+
+		// A new stream may already be running if this one was closed for
+		// being idle.
+		if s.stopped == stopped {
+			s.running = false
+		}
+		close(stopped)
+	}()
+
+	for events := range eventsCh {
+		if ctx.Err() != nil {
+			return
+		}
+		if events.Err != nil {
+			log.Printf("[WARN] error reading from the Nomad event stream of namespace %q: %v", s.namespace, events.Err)
+			return
+		}
+
+		for i := range events.Events {
+			m.dispatch(s, &events.Events[i])
+		}
+	}
+}
+
+func (m *eventMonitor) dispatch(s *eventStream, event *api.Event) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for sub := range s.subs {
+		if sub.topic != event.Topic || !eventMatchesKey(event, sub.key) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			// The subscriber hasn't read the previous event yet, so replace it
+			// with the latest one. This is the only goroutine sending to the
+			// channel, so the second send never blocks.
+			select {
+			case <-sub.events:
+			default:
+			}
+			sub.events <- event
+		}
+	}
+}
+
+func eventMatchesKey(event *api.Event, key string) bool {
+	if event.Key == key {
+		return true
+	}
+	for _, k := range event.FilterKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// monitorDeployment follows the evaluation(s) from a job create/update and, if
+// they result in a deployment, waits for that deployment to complete.
+//
+// If ctx is cancelled while waiting for the deployment, the last known state
+// of the deployment is returned along with the context error.
+func (m *eventMonitor) monitorDeployment(ctx context.Context, namespace, initialEvalID string, timeout time.Duration) (*api.Deployment, error) {
+	deadline := time.After(timeout)
+
+	evaluation, err := m.waitForEvaluation(ctx, namespace, initialEvalID, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	if evaluation.DeploymentID == "" {
+		log.Printf("[WARN] job has been scheduled, but there is no deployment to monitor")
+		return nil, nil
+	}
+
+	return m.waitForDeployment(ctx, namespace, evaluation.DeploymentID, deadline)
+}
+
+// waitForEvaluation follows the chain of evaluations starting at
+// initialEvalID and returns the last one once it completes.
+func (m *eventMonitor) waitForEvaluation(ctx context.Context, namespace, initialEvalID string, deadline <-chan time.Time) (*api.Evaluation, error) {
+	evalID := initialEvalID
+
+	for {
+		sub, err := m.subscribe(namespace, api.TopicEvaluation, evalID)
+		if err != nil {
+			return nil, err
+		}
+
+		eval, err := m.waitForEvaluationComplete(ctx, sub, evalID, deadline)
+		m.unsubscribe(sub)
+		if err != nil {
+			return nil, err
+		}
+
+		if eval.NextEval == "" {
+			return eval, nil
+		}
+
+		log.Printf("[DEBUG] will monitor follow-up eval '%v'", eval.NextEval)
+		evalID = eval.NextEval
+	}
+}
+
+func (m *eventMonitor) waitForEvaluationComplete(ctx context.Context, sub *eventSubscription, evalID string, deadline <-chan time.Time) (*api.Evaluation, error) {
+	// Read the evaluation after subscribing so no update is missed.
+	log.Printf("[DEBUG] monitoring evaluation '%s'", evalID)
+	eval, _, err := m.client.Evaluations().Info(evalID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch eval.Status {
+		case "complete":
+			log.Printf("[DEBUG] evaluation '%v' complete", eval.ID)
+			return eval, nil
+		case "failed", "cancelled":
+			return nil, fmt.Errorf("evaluation failed: %v", eval.StatusDescription)
+		}
+
+		select {
+		case event := <-sub.events:
+			update, err := event.Evaluation()
+			if err != nil || update == nil {
+				log.Printf("[WARN] failed to decode evaluation event: %v", err)
+				continue
+			}
+			eval = update
+		case <-time.After(eventMonitorRefreshInterval):
+			eval, _, err = m.client.Evaluations().Info(evalID, nil)
+			if err != nil {
+				return nil, err
+			}
+		case <-sub.done:
+			return nil, &eventStreamError{err: fmt.Errorf("stream stopped")}
+		case <-deadline:
+			return nil, fmt.Errorf("timeout while waiting for evaluation '%s'", evalID)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// waitForDeployment waits for the deployment to complete successfully. The
+// allocations of the deployment are followed as well to log their progress.
+func (m *eventMonitor) waitForDeployment(ctx context.Context, namespace, deploymentID string, deadline <-chan time.Time) (*api.Deployment, error) {
+	sub, err := m.subscribe(namespace, api.TopicDeployment, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer m.unsubscribe(sub)
+
+	allocSub, err := m.subscribe(namespace, api.TopicAllocation, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer m.unsubscribe(allocSub)
+
+	// Read the deployment after subscribing so no update is missed.
+	deployment, _, err := m.client.Deployments().Info(deploymentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch deployment.Status {
+		case "successful":
+			log.Printf("[DEBUG] deployment '%s' successful", deployment.ID)
+			return deployment, nil
+		case "failed", "cancelled":
+			log.Printf("[DEBUG] deployment unsuccessful: %s", deployment.StatusDescription)
+			return deployment,
+				fmt.Errorf("deployment '%s' terminated with status '%s': '%s'",
+					deployment.ID, deployment.Status, deployment.StatusDescription)
+		}
+
+		select {
+		case event := <-sub.events:
+			update, err := event.Deployment()
+			if err != nil || update == nil {
+				log.Printf("[WARN] failed to decode deployment event: %v", err)
+				continue
+			}
+			deployment = update
+		case event := <-allocSub.events:
+			alloc, err := event.Allocation()
+			if err != nil || alloc == nil {
+				log.Printf("[WARN] failed to decode allocation event: %v", err)
+				continue
+			}
+			logDeploymentAllocation(deploymentID, alloc)
+		case <-time.After(eventMonitorRefreshInterval):
+			deployment, _, err = m.client.Deployments().Info(deploymentID, nil)
+			if err != nil {
+				return nil, err
+			}
+		case <-sub.done:
+			return nil, &eventStreamError{err: fmt.Errorf("stream stopped")}
+		case <-deadline:
+			return nil, fmt.Errorf("timeout while waiting for deployment '%s'", deploymentID)
+		case <-ctx.Done():
+			return deployment, ctx.Err()
+		}
+	}
+}
+
+// logDeploymentAllocation logs the progress of an allocation of a deployment.
+func logDeploymentAllocation(deploymentID string, alloc *api.Allocation) {
+	health := "unknown"
+	if alloc.DeploymentStatus != nil && alloc.DeploymentStatus.Healthy != nil {
+		health = "unhealthy"
+		if *alloc.DeploymentStatus.Healthy {
+			health = "healthy"
+		}
+	}
+
+	log.Printf("[DEBUG] allocation '%s' of deployment '%s' is %s (%s)", alloc.ID, deploymentID, alloc.ClientStatus, health)
+	if alloc.ClientStatus == "failed" {
+		log.Printf("[WARN] allocation '%s' of deployment '%s' failed", alloc.ID, deploymentID)
+	}
+}
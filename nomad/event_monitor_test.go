@@ -0,0 +1,263 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventMonitor_dispatch(t *testing.T) {
+	m := newEventMonitor(nil)
+	s := &eventStream{subs: make(map[*eventSubscription]struct{})}
+
+	evalSub := &eventSubscription{topic: api.TopicEvaluation, key: "eval-1", events: make(chan *api.Event, 1)}
+	deploySub := &eventSubscription{topic: api.TopicDeployment, key: "deploy-1", events: make(chan *api.Event, 1)}
+	jobSub := &eventSubscription{topic: api.TopicDeployment, key: "job-1", events: make(chan *api.Event, 1)}
+	allocSub := &eventSubscription{topic: api.TopicAllocation, key: "deploy-1", events: make(chan *api.Event, 1)}
+	s.subs[evalSub] = struct{}{}
+	s.subs[deploySub] = struct{}{}
+	s.subs[jobSub] = struct{}{}
+	s.subs[allocSub] = struct{}{}
+
+	m.dispatch(s, &api.Event{Topic: api.TopicEvaluation, Key: "eval-2"})
+	m.dispatch(s, &api.Event{Topic: api.TopicEvaluation, Key: "eval-1", Index: 1})
+	m.dispatch(s, &api.Event{Topic: api.TopicEvaluation, Key: "eval-1", Index: 2})
+	m.dispatch(s, &api.Event{Topic: api.TopicEvaluation, Key: "eval-3", FilterKeys: []string{"job-1", "deploy-1"}})
+	m.dispatch(s, &api.Event{Topic: api.TopicDeployment, Key: "deploy-2", FilterKeys: []string{"job-1"}})
+	m.dispatch(s, &api.Event{Topic: api.TopicAllocation, Key: "alloc-1", FilterKeys: []string{"job-1", "deploy-1"}})
+
+	// Only the latest matching event is kept.
+	require.Len(t, evalSub.events, 1)
+	require.Equal(t, uint64(2), (<-evalSub.events).Index)
+
+	// Events are matched by topic, not only by key.
+	require.Len(t, deploySub.events, 0)
+
+	// Events are matched by filter keys.
+	require.Len(t, jobSub.events, 1)
+	require.Equal(t, "deploy-2", (<-jobSub.events).Key)
+	require.Len(t, allocSub.events, 1)
+	require.Equal(t, "alloc-1", (<-allocSub.events).Key)
+}
+
+func TestEventMonitor_idle(t *testing.T) {
+	var lock sync.Mutex
+	var streams []url.Values
+	closed := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		streams = append(streams, r.URL.Query())
+		lock.Unlock()
+
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		closed <- struct{}{}
+	}))
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	streamCount := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(streams)
+	}
+
+	// The stream is kept open while subscribers come and go.
+	m := newEventMonitor(client)
+	m.idleTimeout = time.Hour
+	sub, err := m.subscribe("default", api.TopicEvaluation, "eval-1")
+	require.NoError(t, err)
+	m.unsubscribe(sub)
+	sub, err = m.subscribe("default", api.TopicDeployment, "deploy-1")
+	require.NoError(t, err)
+	require.Equal(t, 1, streamCount())
+	require.ElementsMatch(t, []string{"Evaluation:*", "Deployment:*", "Allocation:*"}, streams[0]["topic"])
+	require.Equal(t, "default", streams[0].Get("namespace"))
+
+	// It is closed once it has no subscribers for the idle timeout.
+	m.lock.Lock()
+	m.idleTimeout = 10 * time.Millisecond
+	m.lock.Unlock()
+	m.unsubscribe(sub)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the idle stream was not closed")
+	}
+
+	// A new stream is opened for the next subscriber.
+	sub, err = m.subscribe("default", api.TopicEvaluation, "eval-2")
+	require.NoError(t, err)
+	require.Equal(t, 2, streamCount())
+
+	// Another stream is opened for each namespace.
+	otherSub, err := m.subscribe("other", api.TopicEvaluation, "eval-3")
+	require.NoError(t, err)
+	require.Equal(t, 3, streamCount())
+	require.Equal(t, "other", streams[2].Get("namespace"))
+
+	m.unsubscribe(sub)
+	m.unsubscribe(otherSub)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the idle stream was not closed")
+		}
+	}
+}
+
+func TestEventMonitor_unavailable(t *testing.T) {
+	var lock sync.Mutex
+	requests := 0
+	available := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests++
+		ok := available
+		lock.Unlock()
+
+		if !ok {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	requestCount := func() int {
+		lock.Lock()
+		defer lock.Unlock()
+		return requests
+	}
+
+	m := newEventMonitor(client)
+	for i := 0; i < 2; i++ {
+		_, err = m.subscribe("default", api.TopicEvaluation, "eval-1")
+		require.Error(t, err)
+		require.IsType(t, &eventStreamError{}, err)
+	}
+
+	// The stream is not requested again before the backoff expires.
+	require.Equal(t, 1, requestCount())
+
+	// Once it expires, the stream is requested again.
+	lock.Lock()
+	available = true
+	lock.Unlock()
+	m.lock.Lock()
+	m.streams["default"].retryAt = time.Now()
+	m.lock.Unlock()
+
+	sub, err := m.subscribe("default", api.TopicEvaluation, "eval-1")
+	require.NoError(t, err)
+	require.Equal(t, 2, requestCount())
+	require.Zero(t, m.streams["default"].failures)
+
+	m.lock.Lock()
+	m.streams["default"].cancel()
+	m.lock.Unlock()
+	m.unsubscribe(sub)
+}
+
+func TestEventMonitorRetryDelay(t *testing.T) {
+	require.Equal(t, eventMonitorRetryMin, eventMonitorRetryDelay(1))
+	require.Equal(t, 2*eventMonitorRetryMin, eventMonitorRetryDelay(2))
+	require.Equal(t, eventMonitorRetryMax, eventMonitorRetryDelay(100))
+}
+
+func TestEventMonitor_monitorDeployment(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/evaluation/eval-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&api.Evaluation{ID: "eval-1", Status: "pending"})
+	})
+	mux.HandleFunc("/v1/deployment/deploy-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&api.Deployment{ID: "deploy-1", Status: "running"})
+	})
+	mux.HandleFunc("/v1/event/stream", func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+
+		// Give the monitor time to subscribe and read the current state.
+		time.Sleep(100 * time.Millisecond)
+		enc.Encode(&api.Events{Index: 1, Events: []api.Event{{
+			Topic: api.TopicEvaluation,
+			Key:   "eval-1",
+			Payload: map[string]interface{}{
+				"Evaluation": map[string]interface{}{
+					"ID":           "eval-1",
+					"Status":       "complete",
+					"DeploymentID": "deploy-1",
+				},
+			},
+		}}})
+		flusher.Flush()
+
+		time.Sleep(100 * time.Millisecond)
+		enc.Encode(&api.Events{Index: 2, Events: []api.Event{{
+			Topic: api.TopicDeployment,
+			Key:   "deploy-1",
+			Payload: map[string]interface{}{
+				"Deployment": map[string]interface{}{
+					"ID":     "deploy-1",
+					"Status": "successful",
+				},
+			},
+		}}})
+		flusher.Flush()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	m := newEventMonitor(client)
+	deployment, err := m.monitorDeployment(context.Background(), "default", "eval-1", 5*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "deploy-1", deployment.ID)
+	require.Equal(t, "successful", deployment.Status)
+}
+
+func TestEventMonitor_monitorDeploymentInterrupted(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/evaluation/eval-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&api.Evaluation{ID: "eval-1", Status: "complete", DeploymentID: "deploy-1"})
+	})
+	mux.HandleFunc("/v1/deployment/deploy-1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&api.Deployment{ID: "deploy-1", Status: "running"})
+	})
+	mux.HandleFunc("/v1/event/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		time.Sleep(time.Second)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// The last known state of the deployment is returned when interrupted.
+	m := newEventMonitor(client)
+	deployment, err := m.monitorDeployment(ctx, "default", "eval-1", 5*time.Second)
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, "deploy-1", deployment.ID)
+	require.Equal(t, "running", deployment.Status)
+}
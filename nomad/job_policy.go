@@ -0,0 +1,345 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+const (
+	jobPolicyWarn = "warn"
+	jobPolicyDeny = "deny"
+)
+
+// jobPolicy is a set of rules checked locally against jobs before they are
+// submitted to Nomad.
+type jobPolicy struct {
+	rules []jobPolicyRule
+}
+
+// jobPolicyRule returns a description of each violation of the rule by a job.
+type jobPolicyRule struct {
+	name        string
+	enforcement string
+	check       func(job *api.Job) []string
+}
+
+func jobPolicySchema() *schema.Schema {
+	rule := func(description string, s map[string]*schema.Schema) *schema.Schema {
+		s["enforcement"] = &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      jobPolicyDeny,
+			Description:  "Whether a violation of the rule fails the plan (`deny`) or is only reported in the `policy_warnings` attribute of the resource (`warn`).",
+			ValidateFunc: validation.StringInSlice([]string{jobPolicyWarn, jobPolicyDeny}, false),
+		}
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: description,
+			Elem:        &schema.Resource{Schema: s},
+		}
+	}
+
+	stringList := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Required:    true,
+			Description: description,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: "Rules checked against the jobs before they are submitted to Nomad.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"denied_drivers": rule("Task drivers that can't be used.", map[string]*schema.Schema{
+					"drivers": stringList("Names of the denied task drivers."),
+				}),
+				"allowed_image_registries": rule("Registries the images of docker and podman tasks can be pulled from.", map[string]*schema.Schema{
+					"registries": stringList("Hostnames of the allowed registries, `docker.io` for Docker Hub."),
+				}),
+				"required_resources": rule("Require every task to set its cpu and memory resources.", map[string]*schema.Schema{}),
+				"required_meta": rule("Meta keys every job must set.", map[string]*schema.Schema{
+					"keys": stringList("The required meta keys."),
+				}),
+				"max_count": rule("Maximum count of each task group.", map[string]*schema.Schema{
+					"count": {
+						Type:         schema.TypeInt,
+						Required:     true,
+						Description:  "The maximum count.",
+						ValidateFunc: validation.IntAtLeast(0),
+					},
+				}),
+				"allowed_namespaces": rule("Namespaces jobs can be registered in.", map[string]*schema.Schema{
+					"namespaces": stringList("Names of the allowed namespaces."),
+				}),
+			},
+		},
+	}
+}
+
+// parseJobPolicy reads the `job_policy` block of the provider configuration.
+// A nil policy is returned if the block is not set.
+func parseJobPolicy(raw interface{}) *jobPolicy {
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 || list[0] == nil {
+		return nil
+	}
+	policyMap := list[0].(map[string]interface{})
+
+	// ruleConfig returns the configuration of a rule, if it's set.
+	ruleConfig := func(name string) (map[string]interface{}, bool) {
+		l, ok := policyMap[name].([]interface{})
+		if !ok || len(l) == 0 {
+			return nil, false
+		}
+		// Rules without arguments are read as nil.
+		m, _ := l[0].(map[string]interface{})
+		if m == nil {
+			m = map[string]interface{}{"enforcement": jobPolicyDeny}
+		}
+		return m, true
+	}
+	enforcement := func(m map[string]interface{}) string {
+		if e, ok := m["enforcement"].(string); ok && e != "" {
+			return e
+		}
+		return jobPolicyDeny
+	}
+	stringSet := func(raw interface{}) map[string]struct{} {
+		set := make(map[string]struct{})
+		l, _ := raw.([]interface{})
+		for _, v := range l {
+			if s, ok := v.(string); ok {
+				set[s] = struct{}{}
+			}
+		}
+		return set
+	}
+
+	policy := &jobPolicy{}
+
+	if m, ok := ruleConfig("denied_drivers"); ok {
+		policy.rules = append(policy.rules, jobPolicyRule{
+			name:        "denied_drivers",
+			enforcement: enforcement(m),
+			check:       checkDeniedDrivers(stringSet(m["drivers"])),
+		})
+	}
+	if m, ok := ruleConfig("allowed_image_registries"); ok {
+		policy.rules = append(policy.rules, jobPolicyRule{
+			name:        "allowed_image_registries",
+			enforcement: enforcement(m),
+			check:       checkAllowedImageRegistries(stringSet(m["registries"])),
+		})
+	}
+	if m, ok := ruleConfig("required_resources"); ok {
+		policy.rules = append(policy.rules, jobPolicyRule{
+			name:        "required_resources",
+			enforcement: enforcement(m),
+			check:       checkRequiredResources,
+		})
+	}
+	if m, ok := ruleConfig("required_meta"); ok {
+		policy.rules = append(policy.rules, jobPolicyRule{
+			name:        "required_meta",
+			enforcement: enforcement(m),
+			check:       checkRequiredMeta(stringSet(m["keys"])),
+		})
+	}
+	if m, ok := ruleConfig("max_count"); ok {
+		policy.rules = append(policy.rules, jobPolicyRule{
+			name:        "max_count",
+			enforcement: enforcement(m),
+			check:       checkMaxCount(m["count"].(int)),
+		})
+	}
+	if m, ok := ruleConfig("allowed_namespaces"); ok {
+		policy.rules = append(policy.rules, jobPolicyRule{
+			name:        "allowed_namespaces",
+			enforcement: enforcement(m),
+			check:       checkAllowedNamespaces(stringSet(m["namespaces"])),
+		})
+	}
+
+	return policy
+}
+
+// policyWarningsSchema is the schema of the `policy_warnings` attribute of
+// the resources registering jobs.
+func policyWarningsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "Violations of the `warn` rules of the provider `job_policy`.",
+		Computed:    true,
+		Type:        schema.TypeList,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// check runs all the rules of the policy against the job. Violations of
+// `warn` rules are returned as warnings, to be set in the `policy_warnings`
+// attribute of the resource, while violations of `deny` rules are returned as
+// an error.
+func (p *jobPolicy) check(job *api.Job) ([]string, error) {
+	warnings := make([]string, 0)
+	if p == nil {
+		return warnings, nil
+	}
+
+	jobID := ""
+	if job.ID != nil {
+		jobID = *job.ID
+	}
+
+	var mErr multierror.Error
+	for _, rule := range p.rules {
+		for _, violation := range rule.check(job) {
+			if rule.enforcement == jobPolicyWarn {
+				log.Printf("[WARN] job %q violates job_policy rule %s: %s", jobID, rule.name, violation)
+				warnings = append(warnings, fmt.Sprintf("%s: %s", rule.name, violation))
+				continue
+			}
+			multierror.Append(&mErr, fmt.Errorf("%s: %s", rule.name, violation))
+		}
+	}
+
+	if err := mErr.ErrorOrNil(); err != nil {
+		return nil, fmt.Errorf("job %q violates the provider job_policy: %v", jobID, err)
+	}
+	return warnings, nil
+}
+
+// forEachTask calls f for each task of the job.
+func forEachTask(job *api.Job, f func(tg *api.TaskGroup, task *api.Task)) {
+	for _, tg := range job.TaskGroups {
+		if tg == nil {
+			continue
+		}
+		for _, task := range tg.Tasks {
+			if task != nil {
+				f(tg, task)
+			}
+		}
+	}
+}
+
+func taskGroupName(tg *api.TaskGroup) string {
+	if tg.Name == nil {
+		return ""
+	}
+	return *tg.Name
+}
+
+func checkDeniedDrivers(drivers map[string]struct{}) func(*api.Job) []string {
+	return func(job *api.Job) []string {
+		var violations []string
+		forEachTask(job, func(tg *api.TaskGroup, task *api.Task) {
+			if _, ok := drivers[task.Driver]; ok {
+				violations = append(violations, fmt.Sprintf("task %q in group %q uses denied driver %q", task.Name, taskGroupName(tg), task.Driver))
+			}
+		})
+		return violations
+	}
+}
+
+func checkAllowedImageRegistries(registries map[string]struct{}) func(*api.Job) []string {
+	return func(job *api.Job) []string {
+		var violations []string
+		forEachTask(job, func(tg *api.TaskGroup, task *api.Task) {
+			if task.Driver != "docker" && task.Driver != "podman" {
+				return
+			}
+			image, _ := task.Config["image"].(string)
+			registry := imageRegistry(image)
+			if _, ok := registries[registry]; !ok {
+				violations = append(violations, fmt.Sprintf("task %q in group %q uses image %q from registry %q which is not allowed", task.Name, taskGroupName(tg), image, registry))
+			}
+		})
+		return violations
+	}
+}
+
+// imageRegistry returns the hostname of the registry of a container image,
+// following the conventions of Docker: the first component of the image name
+// is a registry only if it looks like a hostname.
+func imageRegistry(image string) string {
+	image = strings.TrimPrefix(image, "docker://")
+
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return parts[0]
+	}
+	return "docker.io"
+}
+
+func checkRequiredResources(job *api.Job) []string {
+	var violations []string
+	forEachTask(job, func(tg *api.TaskGroup, task *api.Task) {
+		r := task.Resources
+		if r == nil || r.CPU == nil || *r.CPU == 0 {
+			violations = append(violations, fmt.Sprintf("task %q in group %q doesn't set its cpu resources", task.Name, taskGroupName(tg)))
+		}
+		if r == nil || r.MemoryMB == nil || *r.MemoryMB == 0 {
+			violations = append(violations, fmt.Sprintf("task %q in group %q doesn't set its memory resources", task.Name, taskGroupName(tg)))
+		}
+	})
+	return violations
+}
+
+func checkRequiredMeta(keys map[string]struct{}) func(*api.Job) []string {
+	return func(job *api.Job) []string {
+		var violations []string
+		for key := range keys {
+			if _, ok := job.Meta[key]; !ok {
+				violations = append(violations, fmt.Sprintf("meta key %q is not set", key))
+			}
+		}
+		sort.Strings(violations)
+		return violations
+	}
+}
+
+func checkMaxCount(max int) func(*api.Job) []string {
+	return func(job *api.Job) []string {
+		var violations []string
+		for _, tg := range job.TaskGroups {
+			if tg == nil {
+				continue
+			}
+			// Nomad defaults the count to 1.
+			count := 1
+			if tg.Count != nil {
+				count = *tg.Count
+			}
+			if count > max {
+				violations = append(violations, fmt.Sprintf("group %q has count %d, more than %d", taskGroupName(tg), count, max))
+			}
+		}
+		return violations
+	}
+}
+
+func checkAllowedNamespaces(namespaces map[string]struct{}) func(*api.Job) []string {
+	return func(job *api.Job) []string {
+		namespace := api.DefaultNamespace
+		if job.Namespace != nil && *job.Namespace != "" {
+			namespace = *job.Namespace
+		}
+		if _, ok := namespaces[namespace]; !ok {
+			return []string{fmt.Sprintf("namespace %q is not allowed", namespace)}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,251 @@
+package nomad
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func testJobPolicy(t *testing.T, raw map[string]interface{}) *jobPolicy {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"job_policy": []interface{}{raw},
+	})
+	return parseJobPolicy(d.Get("job_policy"))
+}
+
+func testJobPolicyJob() *api.Job {
+	return &api.Job{
+		ID:        helper.StringToPtr("example"),
+		Namespace: helper.StringToPtr("default"),
+		Meta:      map[string]string{"owner": "team-a"},
+		TaskGroups: []*api.TaskGroup{
+			{
+				Name:  helper.StringToPtr("web"),
+				Count: helper.IntToPtr(3),
+				Tasks: []*api.Task{
+					{
+						Name:   "server",
+						Driver: "docker",
+						Config: map[string]interface{}{"image": "registry.example.com/web:1.0"},
+						Resources: &api.Resources{
+							CPU:      helper.IntToPtr(100),
+							MemoryMB: helper.IntToPtr(128),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestJobPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   map[string]interface{}
+		modify   func(job *api.Job)
+		err      string
+		warnings []string
+	}{
+		{
+			name: "compliant job",
+			policy: map[string]interface{}{
+				"denied_drivers":           []interface{}{map[string]interface{}{"drivers": []interface{}{"raw_exec"}}},
+				"allowed_image_registries": []interface{}{map[string]interface{}{"registries": []interface{}{"registry.example.com"}}},
+				"required_resources":       []interface{}{map[string]interface{}{}},
+				"required_meta":            []interface{}{map[string]interface{}{"keys": []interface{}{"owner"}}},
+				"max_count":                []interface{}{map[string]interface{}{"count": 5}},
+				"allowed_namespaces":       []interface{}{map[string]interface{}{"namespaces": []interface{}{"default"}}},
+			},
+		},
+		{
+			name: "denied driver",
+			policy: map[string]interface{}{
+				"denied_drivers": []interface{}{map[string]interface{}{"drivers": []interface{}{"raw_exec"}}},
+			},
+			modify: func(job *api.Job) { job.TaskGroups[0].Tasks[0].Driver = "raw_exec" },
+			err:    `denied_drivers: task "server" in group "web" uses denied driver "raw_exec"`,
+		},
+		{
+			name: "image from Docker Hub",
+			policy: map[string]interface{}{
+				"allowed_image_registries": []interface{}{map[string]interface{}{"registries": []interface{}{"registry.example.com"}}},
+			},
+			modify: func(job *api.Job) { job.TaskGroups[0].Tasks[0].Config["image"] = "nginx:latest" },
+			err:    `registry "docker.io" which is not allowed`,
+		},
+		{
+			name: "missing resources",
+			policy: map[string]interface{}{
+				"required_resources": []interface{}{map[string]interface{}{}},
+			},
+			modify: func(job *api.Job) { job.TaskGroups[0].Tasks[0].Resources = nil },
+			err:    `doesn't set its cpu resources`,
+		},
+		{
+			name: "missing meta",
+			policy: map[string]interface{}{
+				"required_meta": []interface{}{map[string]interface{}{"keys": []interface{}{"owner", "cost_center"}}},
+			},
+			err: `meta key "cost_center" is not set`,
+		},
+		{
+			name: "count too high",
+			policy: map[string]interface{}{
+				"max_count": []interface{}{map[string]interface{}{"count": 2}},
+			},
+			err: `group "web" has count 3, more than 2`,
+		},
+		{
+			name: "namespace not allowed",
+			policy: map[string]interface{}{
+				"allowed_namespaces": []interface{}{map[string]interface{}{"namespaces": []interface{}{"apps"}}},
+			},
+			err: `namespace "default" is not allowed`,
+		},
+		{
+			name: "warn only",
+			policy: map[string]interface{}{
+				"max_count": []interface{}{map[string]interface{}{"count": 2, "enforcement": "warn"}},
+			},
+			warnings: []string{`max_count: group "web" has count 3, more than 2`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := testJobPolicyJob()
+			if tc.modify != nil {
+				tc.modify(job)
+			}
+
+			warnings, err := testJobPolicy(t, tc.policy).check(job)
+			if tc.err == "" {
+				require.NoError(t, err)
+				if tc.warnings == nil {
+					tc.warnings = []string{}
+				}
+				require.Equal(t, tc.warnings, warnings)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestJobPolicy_unset(t *testing.T) {
+	var policy *jobPolicy
+	warnings, err := policy.check(testJobPolicyJob())
+	require.NoError(t, err)
+	require.Empty(t, warnings)
+	require.Nil(t, parseJobPolicy([]interface{}{}))
+}
+
+func TestImageRegistry(t *testing.T) {
+	testCases := map[string]string{
+		"nginx":                               "docker.io",
+		"library/nginx:1.21":                  "docker.io",
+		"registry.example.com/web:1.0":        "registry.example.com",
+		"registry.example.com:5000/team/web":  "registry.example.com:5000",
+		"localhost/web":                       "localhost",
+		"docker://quay.io/podman/hello":       "quay.io",
+		"docker://docker.io/library/busybox":  "docker.io",
+		"ghcr.io/hashicorp/terraform:1.0.0":   "ghcr.io",
+		"example/app@sha256:0123456789abcdef": "docker.io",
+	}
+
+	for image, expected := range testCases {
+		require.Equal(t, expected, imageRegistry(image), image)
+	}
+}
+
+func TestJobPolicySchema(t *testing.T) {
+	// Each rule has its own enforcement schema.
+	rules := jobPolicySchema().Elem.(*schema.Resource).Schema
+	seen := make(map[*schema.Schema]string)
+	for name, rule := range rules {
+		enforcement := rule.Elem.(*schema.Resource).Schema["enforcement"]
+		require.NotNil(t, enforcement, name)
+		other, ok := seen[enforcement]
+		require.False(t, ok, "rules %s and %s share their enforcement schema", name, other)
+		seen[enforcement] = name
+	}
+}
+
+func TestJobPolicy_plan(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-nomad-job-policy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	jobspec := `
+job "example" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  group "run" {
+    task "run" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/true"
+      }
+    }
+  }
+}
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "example.nomad"), []byte(jobspec), 0644))
+
+	// nomad_job also plans the job with Nomad so it isn't covered here.
+	testCases := []struct {
+		name    string
+		res     *schema.Resource
+		config  map[string]interface{}
+		warning string
+	}{
+		{
+			name:    "jobs",
+			res:     resourceJobs(),
+			config:  map[string]interface{}{"glob": filepath.Join(dir, "*.nomad")},
+			warning: filepath.Join(dir, "example.nomad") + `: denied_drivers: task "run" in group "run" uses denied driver "raw_exec"`,
+		},
+		{
+			name:    "batch run",
+			res:     resourceBatchRun(),
+			config:  map[string]interface{}{"jobspec": jobspec},
+			warning: `denied_drivers: task "run" in group "run" uses denied driver "raw_exec"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := terraform.NewResourceConfigRaw(tc.config)
+
+			deny := ProviderConfig{jobPolicy: testJobPolicy(t, map[string]interface{}{
+				"denied_drivers": []interface{}{map[string]interface{}{
+					"drivers": []interface{}{"raw_exec"},
+				}},
+			})}
+			_, err := tc.res.Diff(nil, config, deny)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), `uses denied driver "raw_exec"`)
+
+			warn := ProviderConfig{jobPolicy: testJobPolicy(t, map[string]interface{}{
+				"denied_drivers": []interface{}{map[string]interface{}{
+					"drivers":     []interface{}{"raw_exec"},
+					"enforcement": "warn",
+				}},
+			})}
+			diff, err := tc.res.Diff(nil, config, warn)
+			require.NoError(t, err)
+			require.Equal(t, "1", diff.Attributes["policy_warnings.#"].New)
+			require.Equal(t, tc.warning, diff.Attributes["policy_warnings.0"].New)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package nomad
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -11,14 +12,20 @@ import (
 )
 
 type ProviderConfig struct {
-	client      *api.Client
-	vaultToken  *string
-	consulToken *string
-	config      *api.Config
+	client       *api.Client
+	vaultToken   *string
+	consulToken  *string
+	config       *api.Config
+	eventMonitor *eventMonitor
+	jobPolicy    *jobPolicy
+
+	// stopCtx is cancelled when Terraform asks the provider to stop, for
+	// example when the user interrupts an apply.
+	stopCtx context.Context
 }
 
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"address": {
 				Type:        schema.TypeString,
@@ -84,6 +91,7 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("CONSUL_HTTP_TOKEN", ""),
 				Description: "Consul token to validate Consul Connect Service Identity policies specified in the job file.",
 			},
+			"job_policy": jobPolicySchema(),
 			"vault_token": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -119,33 +127,48 @@ func Provider() *schema.Provider {
 			},
 		},
 
-		ConfigureFunc: providerConfigure,
-
 		DataSourcesMap: map[string]*schema.Resource{
-			"nomad_acl_policies":     dataSourceAclPolicies(),
-			"nomad_acl_policy":       dataSourceAclPolicy(),
-			"nomad_acl_token":        dataSourceACLToken(),
-			"nomad_acl_tokens":       dataSourceACLTokens(),
-			"nomad_datacenters":      dataSourceDatacenters(),
-			"nomad_deployments":      dataSourceDeployments(),
-			"nomad_job":              dataSourceJob(),
-			"nomad_job_parser":       dataSourceJobParser(),
-			"nomad_namespace":        dataSourceNamespace(),
-			"nomad_namespaces":       dataSourceNamespaces(),
-			"nomad_plugin":           dataSourcePlugin(),
-			"nomad_plugins":          dataSourcePlugins(),
-			"nomad_scaling_policies": dataSourceScalingPolicies(),
-			"nomad_scaling_policy":   dataSourceScalingPolicy(),
-			"nomad_scheduler_config": dataSourceSchedulerConfig(),
-			"nomad_regions":          dataSourceRegions(),
-			"nomad_volumes":          dataSourceVolumes(),
+			"nomad_acl_policies":       dataSourceAclPolicies(),
+			"nomad_acl_policy":         dataSourceAclPolicy(),
+			"nomad_acl_token":          dataSourceACLToken(),
+			"nomad_acl_tokens":         dataSourceACLTokens(),
+			"nomad_agent_self":         dataSourceAgentSelf(),
+			"nomad_allocation":         dataSourceAllocation(),
+			"nomad_allocations":        dataSourceAllocations(),
+			"nomad_autopilot_health":   dataSourceAutopilotHealth(),
+			"nomad_datacenters":        dataSourceDatacenters(),
+			"nomad_deployment":         dataSourceDeployment(),
+			"nomad_deployments":        dataSourceDeployments(),
+			"nomad_evaluation":         dataSourceEvaluation(),
+			"nomad_evaluations":        dataSourceEvaluations(),
+			"nomad_job":                dataSourceJob(),
+			"nomad_job_parser":         dataSourceJobParser(),
+			"nomad_job_summary":        dataSourceJobSummary(),
+			"nomad_job_versions":       dataSourceJobVersions(),
+			"nomad_jobs":               dataSourceJobs(),
+			"nomad_namespace":          dataSourceNamespace(),
+			"nomad_namespaces":         dataSourceNamespaces(),
+			"nomad_node":               dataSourceNode(),
+			"nomad_nodes":              dataSourceNodes(),
+			"nomad_plugin":             dataSourcePlugin(),
+			"nomad_plugins":            dataSourcePlugins(),
+			"nomad_raft_configuration": dataSourceRaftConfiguration(),
+			"nomad_scaling_policies":   dataSourceScalingPolicies(),
+			"nomad_scaling_policy":     dataSourceScalingPolicy(),
+			"nomad_scheduler_config":   dataSourceSchedulerConfig(),
+			"nomad_regions":            dataSourceRegions(),
+			"nomad_server_members":     dataSourceServerMembers(),
+			"nomad_volumes":            dataSourceVolumes(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"nomad_acl_policy":          resourceACLPolicy(),
 			"nomad_acl_token":           resourceACLToken(),
 			"nomad_external_volume":     resourceExternalVolume(),
+			"nomad_batch_run":           resourceBatchRun(),
 			"nomad_job":                 resourceJob(),
+			"nomad_job_evaluate":        resourceJobEvaluate(),
+			"nomad_jobs":                resourceJobs(),
 			"nomad_namespace":           resourceNamespace(),
 			"nomad_quota_specification": resourceQuotaSpecification(),
 			"nomad_sentinel_policy":     resourceSentinelPolicy(),
@@ -153,6 +176,12 @@ func Provider() *schema.Provider {
 			"nomad_scheduler_config":    resourceSchedulerConfig(),
 		},
 	}
+
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, provider.StopContext())
+	}
+
+	return provider
 }
 
 // Get gets the value of the stored token, if any
@@ -168,7 +197,7 @@ func getToken() (string, error) {
 	return token, nil
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(d *schema.ResourceData, stopCtx context.Context) (interface{}, error) {
 	conf := api.DefaultConfig()
 	conf.Address = d.Get("address").(string)
 	conf.Region = d.Get("region").(string)
@@ -227,10 +256,13 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	}
 
 	res := ProviderConfig{
-		config:      conf,
-		client:      client,
-		vaultToken:  &vaultToken,
-		consulToken: &consulToken,
+		config:       conf,
+		client:       client,
+		vaultToken:   &vaultToken,
+		consulToken:  &consulToken,
+		eventMonitor: newEventMonitor(client),
+		jobPolicy:    parseJobPolicy(d.Get("job_policy")),
+		stopCtx:      stopCtx,
 	}
 
 	return res, nil
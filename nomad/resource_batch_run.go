@@ -0,0 +1,459 @@
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// batchRunLogTimeout bounds the time spent reading each task log, which is
+// streamed from the client node the allocation ran on.
+const batchRunLogTimeout = 30 * time.Second
+
+func resourceBatchRun() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceBatchRunCreate,
+		Update:        resourceBatchRunUpdate,
+		Delete:        resourceBatchRunDelete,
+		Read:          resourceBatchRunRead,
+		CustomizeDiff: resourceBatchRunCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"jobspec": {
+				Description: "Job specification of the batch job to run.",
+				Required:    true,
+				ForceNew:    true,
+				Type:        schema.TypeString,
+			},
+
+			"json": {
+				Description: "If true, the `jobspec` will be parsed as json instead of HCL.",
+				Optional:    true,
+				ForceNew:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"hcl2": {
+				Description: "Configuration for the HCL2 jobspec parser.",
+				Optional:    true,
+				ForceNew:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Description: "If true, the `jobspec` will be parsed as HCL2 instead of HCL.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     false,
+						},
+						"allow_fs": {
+							Description: "If true, HCL2 file system functions will be enabled when parsing the `jobspec`.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							ForceNew:    true,
+							Default:     false,
+						},
+						"vars": {
+							Description: "Additional variables to use when templating the job with HCL2",
+							Type:        schema.TypeMap,
+							Optional:    true,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+
+			"triggers": {
+				Description: "Arbitrary values that cause the job to run again when changed.",
+				Optional:    true,
+				ForceNew:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			"purge_on_completion": {
+				Description: "If true, the job is purged from Nomad once it has completed.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"log_tail_bytes": {
+				Description:  "Number of bytes read from the end of the stdout and stderr logs of each task.",
+				Optional:     true,
+				Default:      4096,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"job_id": {
+				Description: "The unique ID the job was registered with.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"namespace": {
+				Description: "The namespace of the job, as derived from the jobspec.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"status": {
+				Description: "Whether the run was `successful` or `failed`.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"policy_warnings": policyWarningsSchema(),
+
+			"allocations": {
+				Description: "The allocations of the run.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"task_group": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"client_status": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"tasks": {
+							Computed: true,
+							Type:     schema.TypeList,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"state": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"failed": {
+										Computed: true,
+										Type:     schema.TypeBool,
+									},
+									"exit_code": {
+										Computed: true,
+										Type:     schema.TypeInt,
+									},
+									"stdout": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+									"stderr": {
+										Computed: true,
+										Type:     schema.TypeString,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// parseBatchRunJob parses the jobspec of the run and checks it against the
+// provider job_policy, returning the violations of its `warn` rules.
+func parseBatchRunJob(d ResourceFieldGetter, providerConfig ProviderConfig) (*api.Job, []string, error) {
+	jobParserConfig, err := parseJobParserConfig(d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job, err := parseJobspec(d.Get("jobspec").(string), jobParserConfig, providerConfig.vaultToken, providerConfig.consulToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Type == nil || *job.Type != "batch" {
+		return nil, nil, fmt.Errorf("job must be of type batch")
+	}
+	if job.IsPeriodic() || job.IsParameterized() {
+		return nil, nil, fmt.Errorf("job can't be periodic or parameterized")
+	}
+
+	if job.Namespace == nil || *job.Namespace == "" {
+		defaultNamespace := "default"
+		job.Namespace = &defaultNamespace
+	}
+
+	policyWarnings, err := providerConfig.jobPolicy.check(job)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, policyWarnings, nil
+}
+
+// resourceBatchRunCustomizeDiff checks the job during plan so an invalid
+// jobspec or a violation of the provider job_policy doesn't only fail the
+// apply.
+func resourceBatchRunCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	// The job is only registered when a new run is created.
+	if d.Id() != "" {
+		return nil
+	}
+
+	if !d.NewValueKnown("jobspec") || !d.NewValueKnown("hcl2") {
+		return d.SetNewComputed("policy_warnings")
+	}
+
+	_, policyWarnings, err := parseBatchRunJob(d, meta.(ProviderConfig))
+	if err != nil {
+		return err
+	}
+	return d.SetNew("policy_warnings", policyWarnings)
+}
+
+func resourceBatchRunCreate(d *schema.ResourceData, meta interface{}) error {
+	providerConfig := meta.(ProviderConfig)
+	client := providerConfig.client
+
+	job, policyWarnings, err := parseBatchRunJob(d, providerConfig)
+	if err != nil {
+		return err
+	}
+	d.Set("policy_warnings", policyWarnings)
+
+	// Each run is registered under its own ID so that it doesn't update a
+	// previous run that is still present in Nomad.
+	if job.Name == nil {
+		job.Name = job.ID
+	}
+	jobID := resource.PrefixedUniqueId(*job.ID + "-")
+	job.ID = &jobID
+
+	log.Printf("[DEBUG] registering batch job %q in namespace %q", jobID, *job.Namespace)
+	_, _, err = client.Jobs().Register(job, nil)
+	if err != nil {
+		return fmt.Errorf("error registering batch job: %s", err)
+	}
+
+	d.SetId(jobID)
+	d.Set("job_id", jobID)
+	d.Set("namespace", job.Namespace)
+
+	opts := &api.QueryOptions{Namespace: *job.Namespace}
+	allocs, err := waitForBatchRun(client, jobID, opts, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	ctx := providerConfig.stopCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	logTailBytes := int64(d.Get("log_tail_bytes").(int))
+	allocsRaw := make([]interface{}, 0, len(allocs))
+	failures := []string{}
+	for _, alloc := range allocs {
+		allocsRaw = append(allocsRaw, batchRunAllocationRaw(ctx, client, alloc, logTailBytes, opts))
+
+		// Only the last attempt of a rescheduled allocation decides the
+		// outcome of the run.
+		if alloc.NextAllocation == "" && alloc.ClientStatus != "complete" {
+			failures = append(failures, fmt.Sprintf("allocation '%s' of task group %q is %s", alloc.ID, alloc.TaskGroup, alloc.ClientStatus))
+		}
+	}
+
+	d.Set("allocations", allocsRaw)
+	if len(failures) == 0 {
+		d.Set("status", "successful")
+	} else {
+		d.Set("status", "failed")
+	}
+
+	if d.Get("purge_on_completion").(bool) {
+		log.Printf("[DEBUG] purging batch job %q", jobID)
+		_, _, err := client.Jobs().Deregister(jobID, true, &api.WriteOptions{Namespace: *job.Namespace})
+		if err != nil {
+			return fmt.Errorf("error purging batch job '%s': %s", jobID, err)
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("batch job '%s' failed:\n%s", jobID, strings.Join(failures, "\n"))
+	}
+
+	return nil
+}
+
+// waitForBatchRun waits until the job is dead and all of its allocations are
+// terminal, and returns the allocations sorted by creation time.
+func waitForBatchRun(client *api.Client, jobID string, opts *api.QueryOptions, timeout time.Duration) ([]*api.Allocation, error) {
+	var stubs []*api.AllocationListStub
+
+	err := resource.Retry(timeout, func() *resource.RetryError {
+		job, _, err := client.Jobs().Info(jobID, opts)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("error reading batch job '%s': %s", jobID, err))
+		}
+		if job.Status == nil || *job.Status != "dead" {
+			return resource.RetryableError(fmt.Errorf("batch job '%s' is still running", jobID))
+		}
+
+		stubs, _, err = client.Jobs().Allocations(jobID, true, opts)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("error listing allocations of batch job '%s': %s", jobID, err))
+		}
+		for _, stub := range stubs {
+			if !allocClientTerminal(stub.ClientStatus) {
+				return resource.RetryableError(fmt.Errorf("allocation '%s' of batch job '%s' is still %s", stub.ID, jobID, stub.ClientStatus))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	allocs := make([]*api.Allocation, 0, len(stubs))
+	for _, stub := range stubs {
+		alloc, _, err := client.Allocations().Info(stub.ID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error reading allocation '%s': %s", stub.ID, err)
+		}
+		allocs = append(allocs, alloc)
+	}
+
+	sort.Slice(allocs, func(i, j int) bool {
+		return allocs[i].CreateIndex < allocs[j].CreateIndex
+	})
+
+	return allocs, nil
+}
+
+func batchRunAllocationRaw(ctx context.Context, client *api.Client, alloc *api.Allocation, logTailBytes int64, opts *api.QueryOptions) map[string]interface{} {
+	taskNames := make([]string, 0, len(alloc.TaskStates))
+	for name := range alloc.TaskStates {
+		taskNames = append(taskNames, name)
+	}
+	sort.Strings(taskNames)
+
+	tasks := make([]interface{}, 0, len(taskNames))
+	for _, name := range taskNames {
+		state := alloc.TaskStates[name]
+		tasks = append(tasks, map[string]interface{}{
+			"name":      name,
+			"state":     state.State,
+			"failed":    state.Failed,
+			"exit_code": taskExitCode(state),
+			"stdout":    taskLogTail(ctx, client, alloc, name, "stdout", logTailBytes, opts),
+			"stderr":    taskLogTail(ctx, client, alloc, name, "stderr", logTailBytes, opts),
+		})
+	}
+
+	return map[string]interface{}{
+		"id":            alloc.ID,
+		"task_group":    alloc.TaskGroup,
+		"client_status": alloc.ClientStatus,
+		"tasks":         tasks,
+	}
+}
+
+// taskExitCode returns the exit code of the last time the task terminated.
+func taskExitCode(state *api.TaskState) int {
+	if state == nil {
+		return 0
+	}
+	for i := len(state.Events) - 1; i >= 0; i-- {
+		if e := state.Events[i]; e != nil && e.Type == api.TaskTerminated {
+			return e.ExitCode
+		}
+	}
+	return 0
+}
+
+// taskLogTail reads the last bytes of a task log. Errors are only logged
+// since the logs may not be available anymore, for example if the client
+// garbage collected the allocation.
+func taskLogTail(ctx context.Context, client *api.Client, alloc *api.Allocation, task, logType string, tailBytes int64, opts *api.QueryOptions) string {
+	if tailBytes == 0 {
+		return ""
+	}
+
+	// The context also cancels the requests made to reach the client node.
+	ctx, cancelCtx := context.WithTimeout(ctx, batchRunLogTimeout)
+	defer cancelCtx()
+	cancel := make(chan struct{})
+	defer close(cancel)
+
+	frames, errCh := client.AllocFS().Logs(alloc, false, task, logType, api.OriginEnd, tailBytes, cancel, opts.WithContext(ctx))
+
+	var buf bytes.Buffer
+	for {
+		select {
+		case frame, ok := <-frames:
+			if !ok {
+				return buf.String()
+			}
+			if frame != nil {
+				buf.Write(frame.Data)
+			}
+		case err := <-errCh:
+			if err != nil {
+				log.Printf("[WARN] error reading %s of task %q in allocation %q: %s", logType, task, alloc.ID, err)
+				return buf.String()
+			}
+		case <-ctx.Done():
+			log.Printf("[WARN] stopped reading %s of task %q in allocation %q: %s", logType, task, alloc.ID, ctx.Err())
+			return buf.String()
+		}
+	}
+}
+
+// resourceBatchRunRead doesn't refresh anything: the results of the run are
+// kept in the state even once the job has been purged or garbage collected.
+func resourceBatchRunRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+// resourceBatchRunUpdate only stores the new values of the arguments that
+// don't cause a new run.
+func resourceBatchRunUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceBatchRunRead(d, meta)
+}
+
+func resourceBatchRunDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	opts := &api.WriteOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+	if opts.Namespace == "" {
+		opts.Namespace = "default"
+	}
+
+	log.Printf("[DEBUG] purging batch job %q", d.Id())
+	_, _, err := client.Jobs().Deregister(d.Id(), true, opts)
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		return fmt.Errorf("error purging batch job '%s': %s", d.Id(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,199 @@
+package nomad
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceBatchRun_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceBatchRun_config("hello", "1", 0, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("nomad_batch_run.test", "job_id", regexp.MustCompile("^tf-batch-run-")),
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "namespace", "default"),
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "status", "successful"),
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "allocations.#", "1"),
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "allocations.0.client_status", "complete"),
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "allocations.0.tasks.0.name", "run"),
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "allocations.0.tasks.0.exit_code", "0"),
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "allocations.0.tasks.0.stdout", "hello\n"),
+				),
+			},
+			{
+				// Changing the triggers runs the job again.
+				Config: testResourceBatchRun_config("hello", "2", 0, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nomad_batch_run.test", "status", "successful"),
+					testResourceBatchRun_checkPurged("nomad_batch_run.test"),
+				),
+			},
+		},
+		CheckDestroy: testResourceBatchRun_checkDestroy,
+	})
+}
+
+func TestResourceBatchRun_failed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testResourceBatchRun_config("oops", "1", 3, false),
+				ExpectError: regexp.MustCompile("batch job 'tf-batch-run-.*' failed"),
+			},
+		},
+		CheckDestroy: testResourceBatchRun_checkDestroy,
+	})
+}
+
+func testResourceBatchRun_config(message, trigger string, exitCode int, purge bool) string {
+	return fmt.Sprintf(`
+resource "nomad_batch_run" "test" {
+  purge_on_completion = %t
+
+  triggers = {
+    run = %q
+  }
+
+  jobspec = <<EOT
+job "tf-batch-run" {
+  datacenters = ["dc1"]
+  type        = "batch"
+
+  group "run" {
+    restart {
+      attempts = 0
+      mode     = "fail"
+    }
+
+    reschedule {
+      attempts  = 0
+      unlimited = false
+    }
+
+    task "run" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sh"
+        args    = ["-c", "echo %s; exit %d"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 32
+      }
+    }
+  }
+}
+EOT
+}
+`, purge, trigger, message, exitCode)
+}
+
+func testResourceBatchRun_checkPurged(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %q not found", name)
+		}
+
+		client := testProvider.Meta().(ProviderConfig).client
+		_, _, err := client.Jobs().Info(rs.Primary.ID, nil)
+		if err == nil {
+			return fmt.Errorf("batch job %q still exists", rs.Primary.ID)
+		}
+		if !strings.Contains(err.Error(), "404") {
+			return err
+		}
+		return nil
+	}
+}
+
+func testResourceBatchRun_checkDestroy(s *terraform.State) error {
+	client := testProvider.Meta().(ProviderConfig).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "nomad_batch_run" {
+			continue
+		}
+
+		job, _, err := client.Jobs().Info(rs.Primary.ID, nil)
+		if err != nil && strings.Contains(err.Error(), "404") {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("batch job %q has not been purged: %v", rs.Primary.ID, *job.Status)
+	}
+
+	return nil
+}
+
+func TestTaskExitCode(t *testing.T) {
+	state := &api.TaskState{
+		Events: []*api.TaskEvent{
+			{Type: api.TaskStarted},
+			{Type: api.TaskTerminated, ExitCode: 1},
+			{Type: api.TaskRestarting},
+			{Type: api.TaskStarted},
+			{Type: api.TaskTerminated, ExitCode: 2},
+			{Type: api.TaskNotRestarting},
+		},
+	}
+	require.Equal(t, 2, taskExitCode(state))
+	require.Equal(t, 0, taskExitCode(&api.TaskState{}))
+	require.Equal(t, 0, taskExitCode(nil))
+}
+
+func TestTaskLogTail_interrupted(t *testing.T) {
+	// The client node can't be reached directly so the logs are streamed
+	// through the server, which sends a frame and then hangs.
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/node/", func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "node not found", http.StatusNotFound)
+	})
+	mux.HandleFunc("/v1/client/fs/logs/", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(api.StreamFrame{Data: []byte("hello"), Offset: 5})
+		w.(http.Flusher).Flush()
+		<-req.Context().Done()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	alloc := &api.Allocation{ID: "alloc", NodeID: "node"}
+	done := make(chan string)
+	go func() {
+		done <- taskLogTail(ctx, client, alloc, "run", "stdout", 1024, &api.QueryOptions{})
+	}()
+
+	select {
+	case out := <-done:
+		require.Equal(t, "hello", out)
+	case <-time.After(5 * time.Second):
+		t.Fatal("reading the log wasn't interrupted")
+	}
+}
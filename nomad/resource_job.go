@@ -1,20 +1,30 @@
 package nomad
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"net"
+	"net/http"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec"
 	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 func resourceJob() *schema.Resource {
@@ -29,14 +39,39 @@ func resourceJob() *schema.Resource {
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(5 * time.Minute),
 			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
 			"jobspec": {
 				Description:      "Job specification. If you want to point to a file use the file() function.",
-				Required:         true,
+				Optional:         true,
 				Type:             schema.TypeString,
 				DiffSuppressFunc: jobspecDiffSuppress,
+				ExactlyOneOf:     []string{"jobspec", "sensitive_jobspec"},
+			},
+
+			"sensitive_jobspec": {
+				Description:      "Job specification, hidden from the plan output. Use instead of `jobspec`.",
+				Optional:         true,
+				Sensitive:        true,
+				Type:             schema.TypeString,
+				DiffSuppressFunc: jobspecDiffSuppress,
+				ExactlyOneOf:     []string{"jobspec", "sensitive_jobspec"},
+			},
+
+			"store_jobspec": {
+				Description:  "How the `jobspec` is stored in the Terraform state. Set to `hash` to only store a SHA-256 hash of the parsed job.",
+				Optional:     true,
+				Default:      "full",
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice([]string{"full", "hash"}, false),
+			},
+
+			"feasibility_checks": {
+				Description: "If true, the job will be checked against the client nodes available in the cluster during plan.",
+				Optional:    true,
+				Type:        schema.TypeBool,
 			},
 
 			"policy_override": {
@@ -66,6 +101,73 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"on_interrupt": {
+				Description:  "What to do with the deployment if monitoring is interrupted. One of `detach`, `pause` or `fail`.",
+				Optional:     true,
+				Default:      "detach",
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice([]string{"detach", "pause", "fail"}, false),
+			},
+
+			"post_deploy_check": {
+				Description: "HTTP probes run against the healthy allocations of a task group once the deployment is successful. Requires detach = false.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"task_group": {
+							Description: "Name of the task group whose allocations are probed.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"port_label": {
+							Description: "Label of the port to probe.",
+							Type:        schema.TypeString,
+							Required:    true,
+						},
+						"path": {
+							Description:  "HTTP path to request.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "/",
+							ValidateFunc: validation.StringMatch(regexp.MustCompile("^/"), "must start with '/'"),
+						},
+						"expected_status": {
+							Description:  "HTTP status code expected in the response.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      200,
+							ValidateFunc: validation.IntBetween(100, 599),
+						},
+						"body_regex": {
+							Description:  "Regular expression the response body must match.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringIsValidRegExp,
+						},
+						"retries": {
+							Description:  "Number of times a failed probe is retried before failing.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      3,
+							ValidateFunc: validation.IntAtLeast(0),
+						},
+						"retry_interval": {
+							Description:  "Time to wait between retries.",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "5s",
+							ValidateFunc: validateDuration,
+						},
+						"rollback": {
+							Description: "If true, the job is reverted to its previous stable version when the probe fails.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+						},
+					},
+				},
+			},
+
 			"deployment_id": {
 				Description: "If detach = false, the ID for the deployment associated with the last job create/update, if one exists.",
 				Computed:    true,
@@ -78,6 +180,8 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeString,
 			},
 
+			"policy_warnings": policyWarningsSchema(),
+
 			"hcl2": {
 				Description: "Configuration for the HCL2 jobspec parser.",
 				Optional:    true,
@@ -112,6 +216,66 @@ func resourceJob() *schema.Resource {
 				Type:        schema.TypeBool,
 			},
 
+			"override": {
+				Description: "Values that are applied to the job after the `jobspec` is parsed.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"datacenters": {
+							Description: "Datacenters to use instead of the ones defined in the jobspec.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"priority": {
+							Description:  "Priority to use instead of the one defined in the jobspec.",
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(1, 100),
+						},
+						"region": {
+							Description: "Region to use instead of the one defined in the jobspec.",
+							Type:        schema.TypeString,
+							Optional:    true,
+						},
+						"meta": {
+							Description: "Job meta values to merge on top of the ones defined in the jobspec.",
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"task_group": {
+							Description: "Overrides for a task group defined in the jobspec.",
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Description: "Name of the task group to override.",
+										Type:        schema.TypeString,
+										Required:    true,
+									},
+									"count": {
+										Description: "Count to use instead of the one defined in the jobspec. A negative value keeps the jobspec count.",
+										Type:        schema.TypeInt,
+										Optional:    true,
+										Default:     -1,
+									},
+									"meta": {
+										Description: "Task group meta values to merge on top of the ones defined in the jobspec.",
+										Type:        schema.TypeMap,
+										Optional:    true,
+										Elem:        &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
 			"modify_index": {
 				Description: "Integer that increments for each change. Used to detect any changes between plan and apply.",
 				Computed:    true,
@@ -160,6 +324,8 @@ func resourceJob() *schema.Resource {
 				},
 			},
 
+			"allocations": allocationsSchema(),
+
 			"task_groups": taskGroupSchema(),
 
 			"purge_on_destroy": {
@@ -167,10 +333,28 @@ func resourceJob() *schema.Resource {
 				Optional:    true,
 				Type:        schema.TypeBool,
 			},
+
+			"stop_children_on_destroy": {
+				Description: "If true, child jobs of a periodic or parameterized job will be deregistered when the resource is destroyed.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
 		},
 	}
 }
 
+// jobspecHashPrefix identifies jobspec values stored as a hash in the state.
+const jobspecHashPrefix = "sha256:"
+
+// jobspecKey returns the attribute the jobspec is set in, either `jobspec` or
+// `sensitive_jobspec`.
+func jobspecKey(d ResourceFieldGetter) string {
+	if d.Get("sensitive_jobspec").(string) != "" {
+		return "sensitive_jobspec"
+	}
+	return "jobspec"
+}
+
 const (
 	MonitoringEvaluation = "monitoring_evaluation"
 	EvaluationComplete   = "evaluation_complete"
@@ -178,6 +362,78 @@ const (
 	DeploymentSuccessful = "deployment_successful"
 )
 
+func allocationsSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "The allocations associated with this job.",
+		Computed:    true,
+		Type:        schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"task_group": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"node_id": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"node_name": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"client_status": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"desired_status": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"job_version": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"ports": allocatedPortsSchema(),
+			},
+		},
+	}
+}
+
+func allocatedPortsSchema() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"label": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"host_ip": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"value": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"to": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+			},
+		},
+	}
+}
+
 func taskGroupSchema() *schema.Schema {
 	return &schema.Schema{
 		Computed: true,
@@ -295,6 +551,37 @@ type HCL2JobParserConfig struct {
 	Vars    map[string]string
 }
 
+// JobOverrides stores values that are applied to the job after the jobspec
+// is parsed.
+type JobOverrides struct {
+	Datacenters []string
+	Priority    int
+	Region      string
+	Meta        map[string]string
+	TaskGroups  []TaskGroupOverrides
+}
+
+// TaskGroupOverrides stores values that are applied to a task group after the
+// jobspec is parsed. A nil Count keeps the value from the jobspec.
+type TaskGroupOverrides struct {
+	Name  string
+	Count *int
+	Meta  map[string]string
+}
+
+// PostDeployCheck is an HTTP probe run against the healthy allocations of a
+// task group once the deployment of the job is successful.
+type PostDeployCheck struct {
+	TaskGroup      string
+	PortLabel      string
+	Path           string
+	ExpectedStatus int
+	BodyRegex      *regexp.Regexp
+	Retries        int
+	RetryInterval  time.Duration
+	Rollback       bool
+}
+
 // ResourceFieldGetter are able to retrieve field values.
 // Examples: *schema.ResourceData and *schema.ResourceDiff
 type ResourceFieldGetter interface {
@@ -311,7 +598,9 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 	client := providerConfig.client
 
 	// Get the jobspec itself.
-	jobspecRaw := d.Get("jobspec").(string)
+	jobspecAttr := jobspecKey(d)
+	jobspecRaw := d.Get(jobspecAttr).(string)
+	storeHash := d.Get("store_jobspec").(string) == "hash"
 
 	// Read job parsing config.
 	jobParserConfig, err := parseJobParserConfig(d)
@@ -319,17 +608,69 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	// When the jobspec is stored as a hash, or was before store_jobspec
+	// changed, and neither the jobspec nor the overrides changed, only
+	// arguments handled by the provider or the way the jobspec is stored
+	// changed so there is nothing to register.
+	if !d.IsNewResource() && !d.HasChange("override") && (storeHash || d.HasChange("store_jobspec")) {
+		oldSpec, _ := d.GetChange(jobspecAttr)
+		if jobspecsEqual(oldSpec.(string), jobspecRaw, jobParserConfig) {
+			log.Printf("[DEBUG] jobspec of job '%s' is unchanged, skipping registration", d.Id())
+
+			stored := jobspecRaw
+			if storeHash && !strings.HasPrefix(jobspecRaw, jobspecHashPrefix) {
+				if stored, err = jobspecHash(jobspecRaw, jobParserConfig); err != nil {
+					return err
+				}
+			}
+			if err := d.Set(jobspecAttr, stored); err != nil {
+				return err
+			}
+			// The job wasn't checked again, keep the warnings it had.
+			policyWarnings, _ := d.GetChange("policy_warnings")
+			if err := d.Set("policy_warnings", policyWarnings); err != nil {
+				return err
+			}
+			return resourceJobRead(d, meta)
+		}
+	}
+
+	// The job can't be registered from the hash of its jobspec.
+	if strings.HasPrefix(jobspecRaw, jobspecHashPrefix) {
+		return fmt.Errorf("job %q can't be registered from the hash of its jobspec, the jobspec must be set", d.Id())
+	}
+
 	// Parse jobspec.
 	job, err := parseJobspec(jobspecRaw, jobParserConfig, providerConfig.vaultToken, providerConfig.consulToken)
 	if err != nil {
 		return err
 	}
 
+	// Compute the hash before registering so the job is not registered if
+	// it can't be stored.
+	var jobspecHashed string
+	if storeHash {
+		jobspecHashed, err = jobspecHash(jobspecRaw, jobParserConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Apply Terraform-side overrides.
+	if err := applyJobOverrides(job, parseJobOverrides(d)); err != nil {
+		return err
+	}
+
 	if job.Namespace == nil || *job.Namespace == "" {
 		defaultNamespace := "default"
 		job.Namespace = &defaultNamespace
 	}
 
+	policyWarnings, err := providerConfig.jobPolicy.check(job)
+	if err != nil {
+		return err
+	}
+
 	// Register the job
 	wantModifyIndexStrI, _ := d.GetChange("modify_index")
 	wantModifyIndex, err := strconv.ParseUint(wantModifyIndexStrI.(string), 10, 64)
@@ -350,10 +691,21 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 	d.Set("name", job.ID)
 	d.Set("namespace", job.Namespace)
 	d.Set("modify_index", strconv.FormatUint(resp.JobModifyIndex, 10))
+	d.Set("policy_warnings", policyWarnings)
+	if jobspecHashed != "" {
+		d.Set(jobspecAttr, jobspecHashed)
+	}
 
 	if d.Get("detach") == false && resp.EvalID != "" {
 		log.Printf("[DEBUG] will monitor scheduling/deployment of job '%s'", *job.ID)
-		deployment, err := monitorDeployment(client, timeout, resp.EvalID)
+		ctx := providerConfig.stopCtx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		deployment, err := monitorDeployment(ctx, client, providerConfig.eventMonitor, timeout, *job.Namespace, resp.EvalID)
+		if err != nil && ctx.Err() != nil {
+			return resourceJobInterrupted(d, meta, deployment)
+		}
 		if err != nil {
 			return fmt.Errorf(
 				"error waiting for job '%s' to schedule/deploy successfully: %s",
@@ -366,6 +718,37 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 			d.Set("deployment_id", nil)
 			d.Set("deployment_status", nil)
 		}
+
+		checks, err := parsePostDeployChecks(d)
+		if err != nil {
+			return err
+		}
+		if len(checks) > 0 {
+			rolledBack, err := resourceJobPostDeployChecks(ctx, client, *job.ID, *job.Namespace, deployment, checks)
+			if err != nil {
+				// A job rolled back on create runs a version registered
+				// outside of Terraform, so it is not added to the state
+				// and the next apply registers it again.
+				if rolledBack && d.IsNewResource() {
+					d.SetId("")
+					return err
+				}
+
+				// The state is saved even though the update failed, so the
+				// previous jobspec is restored after a rollback for the next
+				// plan to show the new jobspec again.
+				if rolledBack {
+					old, _ := d.GetChange(jobspecAttr)
+					d.Set(jobspecAttr, old)
+				}
+				if rerr := resourceJobRead(d, meta); rerr != nil {
+					log.Printf("[WARN] error reading job %q after failed post-deploy checks: %s", *job.ID, rerr)
+				}
+				return err
+			}
+		}
+	} else if _, ok := d.GetOk("post_deploy_check"); ok {
+		log.Printf("[WARN] post_deploy_check of job '%s' are ignored since detach is true", *job.ID)
 	}
 
 	return resourceJobRead(d, meta) // populate other computed attributes
@@ -373,12 +756,32 @@ func resourceJobRegister(d *schema.ResourceData, meta interface{}) error {
 
 // monitorDeployment monitors the evalution(s) from a job create/update and,
 // if they result in a deployment, monitors that deployment until completion.
-func monitorDeployment(client *api.Client, timeout time.Duration, initialEvalID string) (*api.Deployment, error) {
+//
+// Updates are received from the event stream when possible, and polled from
+// the API otherwise. Monitoring stops when ctx is cancelled, in which case the
+// deployment is returned if it's already known.
+func monitorDeployment(ctx context.Context, client *api.Client, monitor *eventMonitor, timeout time.Duration, namespace, initialEvalID string) (*api.Deployment, error) {
+	if monitor != nil {
+		start := time.Now()
+		deployment, err := monitor.monitorDeployment(ctx, namespace, initialEvalID, timeout)
+		if _, ok := err.(*eventStreamError); !ok {
+			return deployment, err
+		}
+
+		log.Printf("[WARN] %v, falling back to polling", err)
+		timeout -= time.Since(start)
+	}
+
+	return pollDeployment(ctx, client, timeout, initialEvalID)
+}
+
+// pollDeployment is the polling version of monitorDeployment.
+func pollDeployment(ctx context.Context, client *api.Client, timeout time.Duration, initialEvalID string) (*api.Deployment, error) {
 
 	stateConf := &resource.StateChangeConf{
 		Pending:    []string{MonitoringEvaluation},
 		Target:     []string{EvaluationComplete},
-		Refresh:    evaluationStateRefreshFunc(client, initialEvalID),
+		Refresh:    contextStateRefreshFunc(ctx, evaluationStateRefreshFunc(client, initialEvalID)),
 		Timeout:    timeout,
 		Delay:      0,
 		MinTimeout: 3 * time.Second,
@@ -398,7 +801,7 @@ func monitorDeployment(client *api.Client, timeout time.Duration, initialEvalID
 	stateConf = &resource.StateChangeConf{
 		Pending:    []string{MonitoringDeployment},
 		Target:     []string{DeploymentSuccessful},
-		Refresh:    deploymentStateRefreshFunc(client, evaluation.DeploymentID),
+		Refresh:    contextStateRefreshFunc(ctx, deploymentStateRefreshFunc(client, evaluation.DeploymentID)),
 		Timeout:    timeout,
 		Delay:      0,
 		MinTimeout: 5 * time.Second,
@@ -406,11 +809,74 @@ func monitorDeployment(client *api.Client, timeout time.Duration, initialEvalID
 
 	state, err = stateConf.WaitForState()
 	if err != nil {
+		if ctx.Err() != nil {
+			return &api.Deployment{ID: evaluation.DeploymentID}, err
+		}
 		return nil, fmt.Errorf("error waiting for evaluation: %s", err)
 	}
 	return state.(*api.Deployment), nil
 }
 
+// contextStateRefreshFunc wraps a resource.StateRefreshFunc so it fails once
+// ctx is cancelled.
+func contextStateRefreshFunc(ctx context.Context, f resource.StateRefreshFunc) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, "", err
+		}
+		return f()
+	}
+}
+
+// resourceJobInterrupted handles the deployment of a job when monitoring was
+// interrupted, according to the `on_interrupt` option, and records the
+// outcome in the state.
+func resourceJobInterrupted(d *schema.ResourceData, meta interface{}, deployment *api.Deployment) error {
+	client := meta.(ProviderConfig).client
+	onInterrupt := d.Get("on_interrupt").(string)
+
+	if deployment == nil {
+		log.Printf("[WARN] monitoring of job %q interrupted before a deployment was created", d.Id())
+		d.Set("deployment_id", nil)
+		d.Set("deployment_status", nil)
+		if err := resourceJobRead(d, meta); err != nil {
+			return err
+		}
+		return fmt.Errorf("monitoring of job '%s' interrupted before a deployment was created", d.Id())
+	}
+
+	switch onInterrupt {
+	case "pause":
+		log.Printf("[DEBUG] pausing deployment %q after interrupt", deployment.ID)
+		if _, _, err := client.Deployments().Pause(deployment.ID, true, nil); err != nil {
+			return fmt.Errorf("error pausing deployment '%s' after interrupt: %s", deployment.ID, err)
+		}
+	case "fail":
+		log.Printf("[DEBUG] failing deployment %q after interrupt", deployment.ID)
+		if _, _, err := client.Deployments().Fail(deployment.ID, nil); err != nil {
+			return fmt.Errorf("error failing deployment '%s' after interrupt: %s", deployment.ID, err)
+		}
+	default:
+		log.Printf("[DEBUG] leaving deployment %q running after interrupt", deployment.ID)
+	}
+
+	// Record the current status of the deployment.
+	d.Set("deployment_id", deployment.ID)
+	d.Set("deployment_status", deployment.Status)
+	if current, _, err := client.Deployments().Info(deployment.ID, nil); err != nil {
+		log.Printf("[WARN] error reading deployment %q after interrupt: %s", deployment.ID, err)
+	} else {
+		d.Set("deployment_status", current.Status)
+	}
+
+	if err := resourceJobRead(d, meta); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("monitoring of job '%s' interrupted, deployment '%s' is %s (on_interrupt = %q)",
+		d.Id(), deployment.ID, d.Get("deployment_status").(string), onInterrupt)
+}
+
 // evaluationStateRefreshFunc returns a resource.StateRefreshFunc that is used to watch
 // the evaluation(s) from a job create/update
 func evaluationStateRefreshFunc(client *api.Client, initialEvalID string) resource.StateRefreshFunc {
@@ -504,9 +970,78 @@ func resourceJobDeregister(d *schema.ResourceData, meta interface{}) error {
 		return fmt.Errorf("error deregistering job: %s", err)
 	}
 
+	if d.Get("stop_children_on_destroy").(bool) {
+		err := deregisterChildJobs(client, id, purge, opts.Namespace, d.Timeout(schema.TimeoutDelete))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// apiResponseStatus returns the HTTP status of the response an error returned
+// by the API client is about, or 0 if the request didn't get a response. The
+// client only reports it in the error message.
+func apiResponseStatus(err error) int {
+	var status int
+	if _, scanErr := fmt.Sscanf(err.Error(), "Unexpected response code: %d", &status); scanErr != nil {
+		return 0
+	}
+	return status
+}
+
+// deregisterChildJobs deregisters the jobs dispatched or launched by a
+// parameterized or periodic job and waits for their allocations to stop.
+func deregisterChildJobs(client *api.Client, parentID string, purge bool, namespace string, timeout time.Duration) error {
+	// Child job IDs are always prefixed by the parent ID.
+	stubs, _, err := client.Jobs().List(&api.QueryOptions{
+		Namespace: namespace,
+		Prefix:    parentID + "/",
+	})
+	if err != nil {
+		return fmt.Errorf("error listing child jobs: %s", err)
+	}
+
+	children := []string{}
+	for _, stub := range stubs {
+		if stub.ParentID != parentID {
+			continue
+		}
+
+		log.Printf("[DEBUG] deregistering child job: %q", stub.ID)
+		_, _, err := client.Jobs().Deregister(stub.ID, purge, &api.WriteOptions{Namespace: namespace})
+		if err != nil {
+			return fmt.Errorf("error deregistering child job %q: %s", stub.ID, err)
+		}
+		children = append(children, stub.ID)
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		for _, child := range children {
+			allocs, _, err := client.Jobs().Allocations(child, false, &api.QueryOptions{Namespace: namespace})
+			if err != nil {
+				// Purged jobs may already be gone.
+				if apiResponseStatus(err) == http.StatusNotFound {
+					continue
+				}
+				return resource.NonRetryableError(fmt.Errorf("error listing allocations for child job %q: %s", child, err))
+			}
+
+			for _, alloc := range allocs {
+				if !allocClientTerminal(alloc.ClientStatus) {
+					return resource.RetryableError(fmt.Errorf("allocation %q of child job %q is still %s", alloc.ID, child, alloc.ClientStatus))
+				}
+			}
+		}
+		return nil
+	})
+}
+
 func resourceJobRead(d *schema.ResourceData, meta interface{}) error {
 	providerConfig := meta.(ProviderConfig)
 	client := providerConfig.client
@@ -533,13 +1068,42 @@ func resourceJobRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	log.Printf("[DEBUG] found job %q in namespace %q", *job.Name, *job.Namespace)
 
-	allocStubs, _, err := client.Jobs().Allocations(id, false, nil)
+	// The allocations of the job are read from the allocation list of the
+	// namespace since, unlike the job allocations endpoint, it can include
+	// the allocated resources and the ports don't need to be read from each
+	// allocation.
+	allocStubs, _, err := client.Allocations().List(&api.QueryOptions{
+		Namespace: opts.Namespace,
+		Params:    map[string]string{"resources": "true"},
+	})
 	if err != nil {
 		log.Printf("[WARN] error listing allocations for Job %q, will return empty list", id)
 	}
-	allocIDs := make([]string, 0, len(allocStubs))
+	allocIDs := make([]string, 0)
+	allocs := make([]interface{}, 0)
 	for _, a := range allocStubs {
+		if a.JobID != id {
+			continue
+		}
 		allocIDs = append(allocIDs, a.ID)
+
+		// Ports are only set for allocations that are still active.
+		var ports []interface{}
+		if !allocClientTerminal(a.ClientStatus) {
+			ports = allocatedPortsRaw(a.AllocatedResources)
+		}
+
+		allocs = append(allocs, map[string]interface{}{
+			"id":             a.ID,
+			"name":           a.Name,
+			"task_group":     a.TaskGroup,
+			"node_id":        a.NodeID,
+			"node_name":      a.NodeName,
+			"client_status":  a.ClientStatus,
+			"desired_status": a.DesiredStatus,
+			"job_version":    int(a.JobVersion),
+			"ports":          ports,
+		})
 	}
 
 	d.Set("name", job.ID)
@@ -548,6 +1112,7 @@ func resourceJobRead(d *schema.ResourceData, meta interface{}) error {
 	d.Set("datacenters", job.Datacenters)
 	d.Set("task_groups", jobTaskGroupsRaw(job.TaskGroups))
 	d.Set("allocation_ids", allocIDs)
+	d.Set("allocations", allocs)
 	d.Set("namespace", job.Namespace)
 	if job.JobModifyIndex != nil {
 		d.Set("modify_index", strconv.FormatUint(*job.JobModifyIndex, 10))
@@ -563,7 +1128,7 @@ func resourceJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
 	providerConfig := meta.(ProviderConfig)
 	client := providerConfig.client
 
-	if !d.NewValueKnown("jobspec") {
+	if !d.NewValueKnown("jobspec") || !d.NewValueKnown("sensitive_jobspec") || !d.NewValueKnown("override") {
 		d.SetNewComputed("name")
 		d.SetNewComputed("modify_index")
 		d.SetNewComputed("namespace")
@@ -571,18 +1136,16 @@ func resourceJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
 		d.SetNewComputed("region")
 		d.SetNewComputed("datacenters")
 		d.SetNewComputed("allocation_ids")
+		d.SetNewComputed("allocations")
 		d.SetNewComputed("task_groups")
 		d.SetNewComputed("deployment_id")
 		d.SetNewComputed("deployment_status")
+		d.SetNewComputed("policy_warnings")
 		return nil
 	}
 
-	oldSpecRaw, newSpecRaw := d.GetChange("jobspec")
-
-	if oldSpecRaw.(string) == newSpecRaw.(string) {
-		// nothing to do!
-		return nil
-	}
+	oldSpecRaw, newSpecRaw := d.GetChange(jobspecKey(d))
+	oldSpec, newSpec := oldSpecRaw.(string), newSpecRaw.(string)
 
 	// Read job parsing config.
 	jobParserConfig, err := parseJobParserConfig(d)
@@ -590,18 +1153,55 @@ func resourceJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
 		return err
 	}
 
+	// When store_jobspec is "hash" and the jobspec didn't change, the new
+	// value is the stored hash. It must be the jobspec itself when
+	// store_jobspec changes since the jobspec is stored again.
+	if strings.HasPrefix(newSpec, jobspecHashPrefix) {
+		if d.HasChange("store_jobspec") {
+			return fmt.Errorf("the jobspec must be set to change store_jobspec")
+		}
+		return nil
+	}
+
+	// Changing store_jobspec only changes how the jobspec is stored, the
+	// job itself is the same.
+	if !d.HasChange("override") && jobspecsEqual(oldSpec, newSpec, jobParserConfig) {
+		// nothing to do!
+		return nil
+	}
+
 	// Parse jobspec
 	// Catch syntax errors client-side during plan
-	job, err := parseJobspec(newSpecRaw.(string), jobParserConfig, providerConfig.vaultToken, providerConfig.consulToken)
+	job, err := parseJobspec(newSpec, jobParserConfig, providerConfig.vaultToken, providerConfig.consulToken)
 	if err != nil {
 		return err
 	}
 
+	// Apply Terraform-side overrides so the plan reflects what will be
+	// registered.
+	if err := applyJobOverrides(job, parseJobOverrides(d)); err != nil {
+		return err
+	}
+
 	defaultNamespace := "default"
 	if job.Namespace == nil || *job.Namespace == "" {
 		job.Namespace = &defaultNamespace
 	}
 
+	policyWarnings, err := providerConfig.jobPolicy.check(job)
+	if err != nil {
+		return err
+	}
+	if err := d.SetNew("policy_warnings", policyWarnings); err != nil {
+		return err
+	}
+
+	if d.Get("feasibility_checks").(bool) {
+		if err := resourceJobFeasibilityChecks(client, job); err != nil {
+			return err
+		}
+	}
+
 	resp, _, err := client.Jobs().PlanOpts(job, &api.PlanOptions{
 		Diff:           false,
 		PolicyOverride: d.Get("policy_override").(bool),
@@ -661,12 +1261,435 @@ func resourceJobCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
 	d.SetNewComputed("modify_index")
 	// similarly, we won't know the allocation ids until after the job registration eval
 	d.SetNewComputed("allocation_ids")
+	d.SetNewComputed("allocations")
 
 	d.SetNew("task_groups", jobTaskGroupsRaw(job.TaskGroups))
 
 	return nil
 }
 
+// allocClientTerminal returns true if the allocation client status is one
+// that will not change anymore.
+func allocClientTerminal(status string) bool {
+	switch status {
+	case "complete", "failed", "lost":
+		return true
+	default:
+		return false
+	}
+}
+
+// allocatedPortsRaw flattens the ports allocated to both the group and task
+// networks into a list sorted by label.
+func allocatedPortsRaw(resources *api.AllocatedResources) []interface{} {
+	ret := make([]interface{}, 0)
+	if resources == nil {
+		return ret
+	}
+
+	seen := make(map[string]struct{})
+	addPort := func(label, hostIP string, value, to int) {
+		if _, ok := seen[label]; ok {
+			return
+		}
+		seen[label] = struct{}{}
+		ret = append(ret, map[string]interface{}{
+			"label":   label,
+			"host_ip": hostIP,
+			"value":   value,
+			"to":      to,
+		})
+	}
+
+	for _, p := range resources.Shared.Ports {
+		addPort(p.Label, p.HostIP, p.Value, p.To)
+	}
+
+	// Tasks are sorted so the same port is kept when several tasks use the
+	// same label.
+	taskNames := make([]string, 0, len(resources.Tasks))
+	for name := range resources.Tasks {
+		taskNames = append(taskNames, name)
+	}
+	sort.Strings(taskNames)
+
+	networks := append([]*api.NetworkResource{}, resources.Shared.Networks...)
+	for _, name := range taskNames {
+		if task := resources.Tasks[name]; task != nil {
+			networks = append(networks, task.Networks...)
+		}
+	}
+	for _, n := range networks {
+		if n == nil {
+			continue
+		}
+		for _, p := range n.ReservedPorts {
+			addPort(p.Label, n.IP, p.Value, p.To)
+		}
+		for _, p := range n.DynamicPorts {
+			addPort(p.Label, n.IP, p.Value, p.To)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].(map[string]interface{})["label"].(string) <
+			ret[j].(map[string]interface{})["label"].(string)
+	})
+
+	return ret
+}
+
+// resourceJobFeasibilityChecks reads the client nodes from the cluster and
+// verifies that the job can be placed on them.
+func resourceJobFeasibilityChecks(client *api.Client, job *api.Job) error {
+	nodes, _, err := client.Nodes().List(nil)
+	if err != nil {
+		return fmt.Errorf("failed to query list of nodes for feasibility checks: %v", err)
+	}
+
+	// Host volumes are only available in the node details, so only read them
+	// if the job needs them.
+	var hostVolumes map[string][]string
+	if jobUsesHostVolumes(job) {
+		hostVolumes = make(map[string][]string)
+		for _, n := range feasibilityCandidateNodes(job, nodes) {
+			node, _, err := client.Nodes().Info(n.ID, nil)
+			if err != nil {
+				return fmt.Errorf("failed to read node %q for feasibility checks: %v", n.ID, err)
+			}
+			for name := range node.HostVolumes {
+				hostVolumes[n.ID] = append(hostVolumes[n.ID], name)
+			}
+		}
+	}
+
+	return checkJobFeasibility(job, nodes, hostVolumes)
+}
+
+// checkJobFeasibility verifies that the datacenters, drivers, node classes and
+// host volumes used by the job are available in the given nodes. hostVolumes
+// maps node IDs to the names of the host volumes they offer. Only the nodes
+// allocations can be placed on are considered, see feasibilityNodeReady.
+func checkJobFeasibility(job *api.Job, nodes []*api.NodeListStub, hostVolumes map[string][]string) error {
+	var mErr *multierror.Error
+	reported := make(map[string]struct{})
+	report := func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		if _, ok := reported[msg]; !ok {
+			reported[msg] = struct{}{}
+			mErr = multierror.Append(mErr, errors.New(msg))
+		}
+	}
+
+	readyNodes := make([]*api.NodeListStub, 0, len(nodes))
+	for _, n := range nodes {
+		if feasibilityNodeReady(n) {
+			readyNodes = append(readyNodes, n)
+		}
+	}
+
+	readyDatacenters := make(map[string]struct{})
+	for _, dc := range filterDatacenters(readyNodes, "", false) {
+		readyDatacenters[dc] = struct{}{}
+	}
+	for _, dc := range job.Datacenters {
+		if _, ok := readyDatacenters[dc]; !ok {
+			report("datacenter %q has no ready nodes", dc)
+		}
+	}
+
+	candidates := feasibilityCandidateNodes(job, nodes)
+
+	healthyDrivers := make(map[string]struct{})
+	nodeClasses := make(map[string]struct{})
+	volumes := make(map[string]struct{})
+	for _, n := range candidates {
+		for name, info := range n.Drivers {
+			if info != nil && info.Healthy {
+				healthyDrivers[name] = struct{}{}
+			}
+		}
+		nodeClasses[n.NodeClass] = struct{}{}
+		for _, name := range hostVolumes[n.ID] {
+			volumes[name] = struct{}{}
+		}
+	}
+
+	checkConstraints := func(constraints []*api.Constraint) {
+		for _, c := range constraints {
+			if c == nil || c.LTarget != "${node.class}" {
+				continue
+			}
+			switch c.Operand {
+			case "=", "==", "is":
+				if _, ok := nodeClasses[c.RTarget]; !ok {
+					report("node class %q is not available on any ready node", c.RTarget)
+				}
+			default:
+				// Other operators are left to the scheduler rather than
+				// evaluated here.
+				log.Printf("[DEBUG] skipping feasibility check of constraint %q %q %q", c.LTarget, c.Operand, c.RTarget)
+			}
+		}
+	}
+
+	checkConstraints(job.Constraints)
+	for _, tg := range job.TaskGroups {
+		checkConstraints(tg.Constraints)
+
+		for _, v := range tg.Volumes {
+			if v == nil || v.Type != "host" {
+				continue
+			}
+			if _, ok := volumes[v.Source]; !ok {
+				report("host volume %q is not offered by any ready node", v.Source)
+			}
+		}
+
+		for _, task := range tg.Tasks {
+			checkConstraints(task.Constraints)
+
+			if _, ok := healthyDrivers[task.Driver]; !ok {
+				report("driver %q is not healthy on any ready node", task.Driver)
+			}
+		}
+	}
+
+	if err := mErr.ErrorOrNil(); err != nil {
+		return fmt.Errorf("job %q failed feasibility checks: %v", *job.ID, err)
+	}
+	return nil
+}
+
+// feasibilityCandidateNodes returns the ready nodes in the datacenters of the
+// job.
+func feasibilityCandidateNodes(job *api.Job, nodes []*api.NodeListStub) []*api.NodeListStub {
+	datacenters := make(map[string]struct{})
+	for _, dc := range job.Datacenters {
+		datacenters[dc] = struct{}{}
+	}
+
+	candidates := []*api.NodeListStub{}
+	for _, n := range nodes {
+		if _, ok := datacenters[n.Datacenter]; ok && feasibilityNodeReady(n) {
+			candidates = append(candidates, n)
+		}
+	}
+	return candidates
+}
+
+// feasibilityNodeReady returns whether allocations can be placed on the node:
+// it must be ready, eligible for scheduling and not draining.
+func feasibilityNodeReady(n *api.NodeListStub) bool {
+	return n.Status == "ready" && n.SchedulingEligibility == "eligible" && !n.Drain
+}
+
+func jobUsesHostVolumes(job *api.Job) bool {
+	for _, tg := range job.TaskGroups {
+		for _, v := range tg.Volumes {
+			if v != nil && v.Type == "host" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// postDeployCheckTimeout is the timeout of each HTTP request made by the
+// post-deploy checks.
+const postDeployCheckTimeout = 10 * time.Second
+
+// resourceJobPostDeployChecks probes the healthy allocations of the job and
+// reverts it to its previous stable version if a failing check asks for it,
+// in which case true is returned along with the error. The job is not
+// reverted if ctx is cancelled while probing.
+func resourceJobPostDeployChecks(ctx context.Context, client *api.Client, jobID, namespace string, deployment *api.Deployment, checks []PostDeployCheck) (bool, error) {
+	opts := &api.QueryOptions{Namespace: namespace}
+
+	// Only the allocations of the deployment are probed when there is one,
+	// since allocations left from previous deployments may still be healthy
+	// while the new version is not.
+	var allocs []*api.AllocationListStub
+	var err error
+	if deployment != nil {
+		allocs, _, err = client.Deployments().Allocations(deployment.ID, opts)
+	} else {
+		allocs, _, err = client.Jobs().Allocations(jobID, false, opts)
+	}
+	if err != nil {
+		return false, fmt.Errorf("error listing allocations for post-deploy checks: %s", err)
+	}
+
+	httpClient := &http.Client{Timeout: postDeployCheckTimeout}
+
+	var mErr multierror.Error
+	rollback := false
+	for _, check := range checks {
+		if err := runPostDeployCheck(ctx, client, httpClient, allocs, deployment, check, opts); err != nil {
+			multierror.Append(&mErr, err)
+			rollback = rollback || check.Rollback
+		}
+	}
+	if mErr.ErrorOrNil() == nil {
+		return false, nil
+	}
+
+	if ctx.Err() != nil {
+		return false, fmt.Errorf("post-deploy checks of job '%s' interrupted: %v", jobID, mErr.ErrorOrNil())
+	}
+
+	err = fmt.Errorf("post-deploy checks of job '%s' failed: %v", jobID, mErr.ErrorOrNil())
+	if !rollback {
+		return false, err
+	}
+
+	version, rerr := revertToStableVersion(client, jobID, namespace)
+	if rerr != nil {
+		return false, fmt.Errorf("%v\nerror rolling back job '%s': %s", err, jobID, rerr)
+	}
+	return true, fmt.Errorf("%v\njob '%s' was rolled back to version %d", err, jobID, version)
+}
+
+// runPostDeployCheck probes each healthy allocation of the task group of the
+// check.
+func runPostDeployCheck(ctx context.Context, client *api.Client, httpClient *http.Client, allocs []*api.AllocationListStub, deployment *api.Deployment, check PostDeployCheck, opts *api.QueryOptions) error {
+	probed := 0
+	for _, stub := range allocs {
+		if stub.TaskGroup != check.TaskGroup || !allocHealthy(stub, deployment) {
+			continue
+		}
+
+		alloc, _, err := client.Allocations().Info(stub.ID, opts)
+		if err != nil {
+			return fmt.Errorf("error reading allocation '%s': %s", stub.ID, err)
+		}
+		address, ok := allocPortAddress(alloc.AllocatedResources, check.PortLabel)
+		if !ok {
+			return fmt.Errorf("port %q not found in allocation '%s'", check.PortLabel, stub.ID)
+		}
+
+		log.Printf("[DEBUG] running post-deploy check of allocation %q on %s", stub.ID, address)
+		if err := probePostDeployCheck(ctx, httpClient, address, check); err != nil {
+			return fmt.Errorf("allocation '%s' of task group %q: %s", stub.ID, check.TaskGroup, err)
+		}
+		probed++
+	}
+
+	if probed == 0 {
+		return fmt.Errorf("no healthy allocation found for task group %q", check.TaskGroup)
+	}
+	return nil
+}
+
+// allocHealthy returns true if the allocation is running and, when the job
+// was deployed, was marked healthy by the deployment.
+func allocHealthy(alloc *api.AllocationListStub, deployment *api.Deployment) bool {
+	if alloc.ClientStatus != "running" || alloc.DesiredStatus != "run" {
+		return false
+	}
+	if deployment == nil {
+		return true
+	}
+	return alloc.DeploymentStatus != nil &&
+		alloc.DeploymentStatus.Healthy != nil &&
+		*alloc.DeploymentStatus.Healthy
+}
+
+// allocPortAddress returns the host address of the port with the given label.
+func allocPortAddress(resources *api.AllocatedResources, label string) (string, bool) {
+	for _, raw := range allocatedPortsRaw(resources) {
+		port := raw.(map[string]interface{})
+		if port["label"].(string) == label {
+			return net.JoinHostPort(port["host_ip"].(string), strconv.Itoa(port["value"].(int))), true
+		}
+	}
+	return "", false
+}
+
+// probePostDeployCheck requests the path of the check on address until the
+// response is the one expected, the retries are exhausted or ctx is
+// cancelled.
+func probePostDeployCheck(ctx context.Context, httpClient *http.Client, address string, check PostDeployCheck) error {
+	url := "http://" + address + check.Path
+
+	var err error
+	for attempt := 0; attempt <= check.Retries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(check.RetryInterval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("%s, interrupted before attempt %d of %d: %s", err, attempt+1, check.Retries+1, ctx.Err())
+			}
+		}
+
+		err = probeHTTP(ctx, httpClient, url, check)
+		if err == nil {
+			return nil
+		}
+		log.Printf("[DEBUG] post-deploy check of %s failed (attempt %d of %d): %s", url, attempt+1, check.Retries+1, err)
+	}
+	return err
+}
+
+func probeHTTP(ctx context.Context, httpClient *http.Client, url string, check PostDeployCheck) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != check.ExpectedStatus {
+		return fmt.Errorf("GET %s returned status %d, expected %d", url, resp.StatusCode, check.ExpectedStatus)
+	}
+
+	if check.BodyRegex != nil {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("error reading response of GET %s: %s", url, err)
+		}
+		if !check.BodyRegex.Match(body) {
+			return fmt.Errorf("response of GET %s doesn't match %q", url, check.BodyRegex.String())
+		}
+	}
+
+	return nil
+}
+
+// revertToStableVersion reverts the job to the latest stable version older
+// than the current one and returns that version.
+func revertToStableVersion(client *api.Client, jobID, namespace string) (uint64, error) {
+	versions, _, _, err := client.Jobs().Versions(jobID, false, &api.QueryOptions{Namespace: namespace})
+	if err != nil {
+		return 0, fmt.Errorf("error reading job versions: %s", err)
+	}
+	if len(versions) == 0 || versions[0].Version == nil {
+		return 0, fmt.Errorf("no version found")
+	}
+
+	// Versions are sorted from the most recent one.
+	current := *versions[0].Version
+	for _, v := range versions[1:] {
+		if v.Version == nil || v.Stable == nil || !*v.Stable {
+			continue
+		}
+
+		log.Printf("[DEBUG] reverting job %q from version %d to %d", jobID, current, *v.Version)
+		_, _, err := client.Jobs().Revert(jobID, *v.Version, &current, &api.WriteOptions{Namespace: namespace}, "", "")
+		if err != nil {
+			return 0, err
+		}
+		return *v.Version, nil
+	}
+
+	return 0, fmt.Errorf("no previous stable version to roll back to")
+}
+
 func parseJobParserConfig(d ResourceFieldGetter) (JobParserConfig, error) {
 	config := JobParserConfig{}
 
@@ -728,6 +1751,172 @@ func parseHCL2JobParserConfig(raw interface{}) (HCL2JobParserConfig, error) {
 	return config, nil
 }
 
+func parseJobOverrides(d ResourceFieldGetter) JobOverrides {
+	overrides := JobOverrides{}
+
+	// `override` is a list with at most one element.
+	overrideList, ok := d.Get("override").([]interface{})
+	if !ok || len(overrideList) == 0 {
+		return overrides
+	}
+	overrideMap, ok := overrideList[0].(map[string]interface{})
+	if !ok {
+		return overrides
+	}
+
+	if dcs, ok := overrideMap["datacenters"].([]interface{}); ok {
+		for _, dc := range dcs {
+			overrides.Datacenters = append(overrides.Datacenters, dc.(string))
+		}
+	}
+	if priority, ok := overrideMap["priority"].(int); ok {
+		overrides.Priority = priority
+	}
+	if region, ok := overrideMap["region"].(string); ok {
+		overrides.Region = region
+	}
+	overrides.Meta = stringMap(overrideMap["meta"])
+
+	if tgs, ok := overrideMap["task_group"].([]interface{}); ok {
+		for _, tgRaw := range tgs {
+			tgMap, ok := tgRaw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			tg := TaskGroupOverrides{
+				Name: tgMap["name"].(string),
+				Meta: stringMap(tgMap["meta"]),
+			}
+			if count, ok := tgMap["count"].(int); ok && count >= 0 {
+				tg.Count = &count
+			}
+			overrides.TaskGroups = append(overrides.TaskGroups, tg)
+		}
+	}
+
+	return overrides
+}
+
+// applyJobOverrides modifies the parsed job with the values set in the
+// `override` block.
+func applyJobOverrides(job *api.Job, overrides JobOverrides) error {
+	if len(overrides.Datacenters) > 0 {
+		job.Datacenters = overrides.Datacenters
+	}
+	if overrides.Priority != 0 {
+		priority := overrides.Priority
+		job.Priority = &priority
+	}
+	if overrides.Region != "" {
+		region := overrides.Region
+		job.Region = &region
+	}
+	if len(overrides.Meta) > 0 {
+		if job.Meta == nil {
+			job.Meta = make(map[string]string)
+		}
+		for k, v := range overrides.Meta {
+			job.Meta[k] = v
+		}
+	}
+
+	for _, tgOverride := range overrides.TaskGroups {
+		var tg *api.TaskGroup
+		for _, t := range job.TaskGroups {
+			if t.Name != nil && *t.Name == tgOverride.Name {
+				tg = t
+				break
+			}
+		}
+		if tg == nil {
+			return fmt.Errorf("error applying overrides: task group %q not found in jobspec", tgOverride.Name)
+		}
+
+		if tgOverride.Count != nil {
+			count := *tgOverride.Count
+			tg.Count = &count
+		}
+		if len(tgOverride.Meta) > 0 {
+			if tg.Meta == nil {
+				tg.Meta = make(map[string]string)
+			}
+			for k, v := range tgOverride.Meta {
+				tg.Meta[k] = v
+			}
+		}
+	}
+
+	return nil
+}
+
+func parsePostDeployChecks(d ResourceFieldGetter) ([]PostDeployCheck, error) {
+	checksRaw, ok := d.Get("post_deploy_check").([]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	checks := make([]PostDeployCheck, 0, len(checksRaw))
+	for _, raw := range checksRaw {
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		check := PostDeployCheck{
+			TaskGroup:      m["task_group"].(string),
+			PortLabel:      m["port_label"].(string),
+			Path:           m["path"].(string),
+			ExpectedStatus: m["expected_status"].(int),
+			Retries:        m["retries"].(int),
+			Rollback:       m["rollback"].(bool),
+		}
+
+		interval, err := time.ParseDuration(m["retry_interval"].(string))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing post_deploy_check retry_interval: %s", err)
+		}
+		check.RetryInterval = interval
+
+		if expr := m["body_regex"].(string); expr != "" {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing post_deploy_check body_regex: %s", err)
+			}
+			check.BodyRegex = re
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks, nil
+}
+
+func validateDuration(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return
+	}
+	if _, err := time.ParseDuration(v); err != nil {
+		errors = append(errors, fmt.Errorf("%q: invalid duration %q: %s", k, v, err))
+	}
+	return
+}
+
+func stringMap(raw interface{}) map[string]string {
+	m, ok := raw.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil
+	}
+
+	ret := make(map[string]string, len(m))
+	for k, v := range m {
+		ret[k] = v.(string)
+	}
+	return ret
+}
+
 func parseJobspec(raw string, config JobParserConfig, vaultToken *string, consulToken *string) (*api.Job, error) {
 	var job *api.Job
 	var err error
@@ -757,6 +1946,48 @@ func parseJobspec(raw string, config JobParserConfig, vaultToken *string, consul
 	return job, nil
 }
 
+// jobspecHash returns the SHA-256 hash of the canonicalized parsed job. The
+// Vault and Consul tokens and the `override` values are not included.
+func jobspecHash(raw string, config JobParserConfig) (string, error) {
+	job, err := parseJobspec(raw, config, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	job.Canonicalize()
+
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return "", fmt.Errorf("error hashing jobspec: %s", err)
+	}
+
+	sum := sha256.Sum256(jobJSON)
+	return jobspecHashPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// jobspecsEqual returns whether old and new describe the same job, each of
+// them being either a jobspec or the hash of one.
+func jobspecsEqual(old, new string, config JobParserConfig) bool {
+	hash := func(raw string) (string, error) {
+		if strings.HasPrefix(raw, jobspecHashPrefix) {
+			return raw, nil
+		}
+		return jobspecHash(raw, config)
+	}
+
+	if old == new {
+		return true
+	}
+	oldHash, err := hash(old)
+	if err != nil {
+		return false
+	}
+	newHash, err := hash(new)
+	if err != nil {
+		return false
+	}
+	return oldHash == newHash
+}
+
 func parseJSONJobspec(raw string) (*api.Job, error) {
 	// `nomad job run -output` returns a jobspec with a "Job" root, so
 	// partially parse the input JSON to detect if we have this root.
@@ -887,6 +2118,22 @@ func jobspecDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
 		return false
 	}
 
+	// Jobspecs stored as a hash are compared against the hash of the new
+	// jobspec. The diff is kept when the overrides change since the job
+	// can't be registered again from the hash alone, and when store_jobspec
+	// changes so the full jobspec gets stored again.
+	if strings.HasPrefix(old, jobspecHashPrefix) {
+		if d.HasChange("override") || d.HasChange("store_jobspec") {
+			return false
+		}
+		newHash, err := jobspecHash(new, jobParserConfig)
+		if err != nil {
+			log.Printf("[ERROR] %v", err)
+			return false
+		}
+		return old == newHash
+	}
+
 	switch {
 	case jobParserConfig.JSON.Enabled:
 		oldJob, oldErr = parseJSONJobspec(old)
@@ -911,6 +2158,18 @@ func jobspecDiffSuppress(k, old, new string, d *schema.ResourceData) bool {
 		return false
 	}
 
+	// Apply the same overrides to both sides so only jobspec changes are
+	// compared.
+	overrides := parseJobOverrides(d)
+	if err := applyJobOverrides(oldJob, overrides); err != nil {
+		log.Printf("[ERROR] %v", err)
+		return false
+	}
+	if err := applyJobOverrides(newJob, overrides); err != nil {
+		log.Printf("[ERROR] %v", err)
+		return false
+	}
+
 	// Init
 	oldJob.Canonicalize()
 	newJob.Canonicalize()
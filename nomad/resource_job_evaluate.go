@@ -0,0 +1,217 @@
+package nomad
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceJobEvaluate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceJobEvaluateCreate,
+		Delete: resourceJobEvaluateDelete,
+		Read:   resourceJobEvaluateRead,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"job_id": {
+				Description: "The ID of the job to evaluate.",
+				Required:    true,
+				ForceNew:    true,
+				Type:        schema.TypeString,
+			},
+
+			"namespace": {
+				Description: "The namespace of the job.",
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "default",
+				Type:        schema.TypeString,
+			},
+
+			"force_reschedule": {
+				Description: "If true, failed allocations of the job are rescheduled, ignoring their reschedule policy.",
+				Optional:    true,
+				ForceNew:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"triggers": {
+				Description: "Arbitrary values that cause the job to be evaluated again when changed.",
+				Optional:    true,
+				ForceNew:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			"eval_id": {
+				Description: "The ID of the last evaluation in the chain.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"eval_status": {
+				Description: "The status of the last evaluation in the chain.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"blocked_eval_id": {
+				Description: "The ID of the evaluation created for the allocations that could not be placed, if any.",
+				Computed:    true,
+				Type:        schema.TypeString,
+			},
+
+			"placement_failures": placementFailuresSchema(),
+		},
+	}
+}
+
+func placementFailuresSchema() *schema.Schema {
+	return &schema.Schema{
+		Description: "The task groups that could not be placed.",
+		Computed:    true,
+		Type:        schema.TypeList,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"task_group": {
+					Computed: true,
+					Type:     schema.TypeString,
+				},
+				"coalesced_failures": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"nodes_evaluated": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"nodes_filtered": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"nodes_exhausted": {
+					Computed: true,
+					Type:     schema.TypeInt,
+				},
+				"class_filtered": {
+					Computed: true,
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeInt},
+				},
+				"constraint_filtered": {
+					Computed: true,
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeInt},
+				},
+				"class_exhausted": {
+					Computed: true,
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeInt},
+				},
+				"dimension_exhausted": {
+					Computed: true,
+					Type:     schema.TypeMap,
+					Elem:     &schema.Schema{Type: schema.TypeInt},
+				},
+				"quota_exhausted": {
+					Computed: true,
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+}
+
+func resourceJobEvaluateCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	jobID := d.Get("job_id").(string)
+	opts := api.EvalOptions{
+		ForceReschedule: d.Get("force_reschedule").(bool),
+	}
+	wOpts := &api.WriteOptions{
+		Namespace: d.Get("namespace").(string),
+	}
+
+	log.Printf("[DEBUG] evaluating job %q in namespace %q", jobID, wOpts.Namespace)
+	evalID, _, err := client.Jobs().EvaluateWithOpts(jobID, opts, wOpts)
+	if err != nil {
+		return fmt.Errorf("error evaluating job '%s': %s", jobID, err)
+	}
+	d.SetId(evalID)
+
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{MonitoringEvaluation},
+		Target:     []string{EvaluationComplete},
+		Refresh:    evaluationStateRefreshFunc(client, evalID),
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		Delay:      0,
+		MinTimeout: 3 * time.Second,
+	}
+
+	state, err := stateConf.WaitForState()
+	if err != nil {
+		return fmt.Errorf("error waiting for evaluation of job '%s': %s", jobID, err)
+	}
+	eval := state.(*api.Evaluation)
+
+	d.Set("eval_id", eval.ID)
+	d.Set("eval_status", eval.Status)
+	d.Set("blocked_eval_id", eval.BlockedEval)
+	d.Set("placement_failures", placementFailuresRaw(eval.FailedTGAllocs))
+
+	return nil
+}
+
+// placementFailuresRaw flattens the allocation metrics of the task groups
+// that failed to be placed into a list sorted by task group.
+func placementFailuresRaw(failed map[string]*api.AllocationMetric) []interface{} {
+	tgs := make([]string, 0, len(failed))
+	for tg := range failed {
+		tgs = append(tgs, tg)
+	}
+	sort.Strings(tgs)
+
+	ret := make([]interface{}, 0, len(tgs))
+	for _, tg := range tgs {
+		metric := failed[tg]
+		if metric == nil {
+			continue
+		}
+		ret = append(ret, map[string]interface{}{
+			"task_group":          tg,
+			"coalesced_failures":  metric.CoalescedFailures,
+			"nodes_evaluated":     metric.NodesEvaluated,
+			"nodes_filtered":      metric.NodesFiltered,
+			"nodes_exhausted":     metric.NodesExhausted,
+			"class_filtered":      metric.ClassFiltered,
+			"constraint_filtered": metric.ConstraintFiltered,
+			"class_exhausted":     metric.ClassExhausted,
+			"dimension_exhausted": metric.DimensionExhausted,
+			"quota_exhausted":     metric.QuotaExhausted,
+		})
+	}
+
+	return ret
+}
+
+// resourceJobEvaluateRead doesn't refresh anything: the resource records the
+// outcome of an evaluation, which doesn't change once it has completed.
+func resourceJobEvaluateRead(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
+
+func resourceJobEvaluateDelete(d *schema.ResourceData, meta interface{}) error {
+	// Evaluations can't be deleted, they are garbage collected by Nomad.
+	return nil
+}
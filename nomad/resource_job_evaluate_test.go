@@ -0,0 +1,158 @@
+package nomad
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceJobEvaluate_basic(t *testing.T) {
+	var firstEvalID string
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testResourceJobEvaluate_config("1", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nomad_job_evaluate.test", "eval_status", "complete"),
+					resource.TestCheckResourceAttr("nomad_job_evaluate.test", "placement_failures.#", "0"),
+					resource.TestCheckResourceAttr("nomad_job_evaluate.test", "blocked_eval_id", ""),
+					resource.TestCheckResourceAttrSet("nomad_job_evaluate.test", "eval_id"),
+					testResourceJobEvaluate_storeEvalID(&firstEvalID),
+				),
+			},
+			{
+				// Changing the triggers evaluates the job again.
+				Config: testResourceJobEvaluate_config("2", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nomad_job_evaluate.test", "eval_status", "complete"),
+					testResourceJobEvaluate_checkNewEval(&firstEvalID),
+				),
+			},
+			{
+				// A task group requesting more memory than available can't be
+				// placed.
+				Config: testResourceJobEvaluate_config("3", 1000000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nomad_job_evaluate.test", "eval_status", "complete"),
+					resource.TestCheckResourceAttr("nomad_job_evaluate.test", "placement_failures.#", "1"),
+					resource.TestCheckResourceAttr("nomad_job_evaluate.test", "placement_failures.0.task_group", "foo"),
+					resource.TestCheckResourceAttrSet("nomad_job_evaluate.test", "blocked_eval_id"),
+				),
+			},
+		},
+	})
+}
+
+func testResourceJobEvaluate_config(trigger string, memory int) string {
+	return fmt.Sprintf(`
+resource "nomad_job" "test" {
+  jobspec = <<EOT
+job "tf-job-evaluate" {
+  datacenters = ["dc1"]
+
+  group "foo" {
+    task "foo" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["10"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = %d
+      }
+    }
+  }
+}
+EOT
+}
+
+resource "nomad_job_evaluate" "test" {
+  job_id           = nomad_job.test.id
+  force_reschedule = true
+
+  triggers = {
+    run = %q
+  }
+}
+`, memory, trigger)
+}
+
+func testResourceJobEvaluate_storeEvalID(evalID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["nomad_job_evaluate.test"]
+		if !ok {
+			return fmt.Errorf("resource not found in state")
+		}
+		*evalID = rs.Primary.ID
+		return nil
+	}
+}
+
+func testResourceJobEvaluate_checkNewEval(previous *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["nomad_job_evaluate.test"]
+		if !ok {
+			return fmt.Errorf("resource not found in state")
+		}
+		if rs.Primary.ID == *previous {
+			return fmt.Errorf("job was not evaluated again")
+		}
+		return nil
+	}
+}
+
+func TestPlacementFailuresRaw(t *testing.T) {
+	failed := map[string]*api.AllocationMetric{
+		"web": {
+			NodesEvaluated:     3,
+			NodesFiltered:      1,
+			NodesExhausted:     2,
+			CoalescedFailures:  4,
+			ConstraintFiltered: map[string]int{"${attr.kernel.name} = windows": 1},
+			DimensionExhausted: map[string]int{"memory": 2},
+		},
+		"db": {
+			NodesEvaluated: 3,
+			QuotaExhausted: []string{"memory"},
+		},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{
+			"task_group":          "db",
+			"coalesced_failures":  0,
+			"nodes_evaluated":     3,
+			"nodes_filtered":      0,
+			"nodes_exhausted":     0,
+			"class_filtered":      map[string]int(nil),
+			"constraint_filtered": map[string]int(nil),
+			"class_exhausted":     map[string]int(nil),
+			"dimension_exhausted": map[string]int(nil),
+			"quota_exhausted":     []string{"memory"},
+		},
+		map[string]interface{}{
+			"task_group":          "web",
+			"coalesced_failures":  4,
+			"nodes_evaluated":     3,
+			"nodes_filtered":      1,
+			"nodes_exhausted":     2,
+			"class_filtered":      map[string]int(nil),
+			"constraint_filtered": map[string]int{"${attr.kernel.name} = windows": 1},
+			"class_exhausted":     map[string]int(nil),
+			"dimension_exhausted": map[string]int{"memory": 2},
+			"quota_exhausted":     []string(nil),
+		},
+	}
+	require.Equal(t, expected, placementFailuresRaw(failed))
+	require.Empty(t, placementFailuresRaw(nil))
+}
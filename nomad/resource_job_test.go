@@ -1,14 +1,19 @@
 package nomad
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -1202,6 +1207,19 @@ func testResourceJob_initialCheckNS(t *testing.T, expectedNamespace string) r.Te
 			return fmt.Errorf("job 'allocation_ids' is '%v'; want '%v'", gotAllocs, wantAllocIds)
 		}
 
+		numGotAllocDetails, _ := strconv.Atoi(instanceState.Attributes["allocations.#"])
+		gotAllocDetails := make([]string, 0, numGotAllocDetails)
+		for i := 0; i < numGotAllocDetails; i++ {
+			id := instanceState.Attributes[fmt.Sprintf("allocations.%d.id", i)]
+			gotAllocDetails = append(gotAllocDetails, id)
+			if tg := instanceState.Attributes[fmt.Sprintf("allocations.%d.task_group", i)]; tg != "foo" {
+				return fmt.Errorf("allocation %q task_group is %q; want %q", id, tg, "foo")
+			}
+		}
+		if !assert.ElementsMatch(t, gotAllocDetails, wantAllocIds) {
+			return fmt.Errorf("job 'allocations' is '%v'; want '%v'", gotAllocDetails, wantAllocIds)
+		}
+
 		return nil
 	}
 }
@@ -3402,3 +3420,1256 @@ job "example" {
 		})
 	}
 }
+
+func TestResourceJob_override(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config: testResourceJob_overrideConfig(2, "high"),
+				Check:  testResourceJob_overrideCheck(2, "high"),
+			},
+			{
+				Config: testResourceJob_overrideConfig(3, "low"),
+				Check:  testResourceJob_overrideCheck(3, "low"),
+			},
+		},
+		CheckDestroy: testResourceJob_checkDestroy("foo-override"),
+	})
+}
+
+func testResourceJob_overrideConfig(count int, tier string) string {
+	return fmt.Sprintf(`
+resource "nomad_job" "override" {
+	override {
+		datacenters = ["dc1"]
+		priority    = 70
+		meta = {
+			"tier" = "%[2]s"
+		}
+
+		task_group {
+			name  = "foo"
+			count = %[1]d
+			meta = {
+				"tier" = "%[2]s"
+			}
+		}
+	}
+
+	jobspec = <<EOT
+		job "foo-override" {
+			datacenters = ["dc2"]
+			type = "service"
+			meta {
+				tier = "default"
+				team = "infra"
+			}
+			group "foo" {
+				count = 1
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/sleep"
+						args = ["10"]
+					}
+
+					resources {
+						cpu = 100
+						memory = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+`, count, tier)
+}
+
+func testResourceJob_overrideCheck(count int, tier string) r.TestCheckFunc {
+	return func(s *terraform.State) error {
+		providerConfig := testProvider.Meta().(ProviderConfig)
+		client := providerConfig.client
+		job, _, err := client.Jobs().Info("foo-override", nil)
+		if err != nil {
+			return fmt.Errorf("error reading back job: %s", err)
+		}
+
+		if diff := cmp.Diff(job.Datacenters, []string{"dc1"}); diff != "" {
+			return fmt.Errorf("datacenters mismatch (-want +got):\n%s", diff)
+		}
+		if got, want := *job.Priority, 70; got != want {
+			return fmt.Errorf("priority is %d; want %d", got, want)
+		}
+		if diff := cmp.Diff(job.Meta, map[string]string{"tier": tier, "team": "infra"}); diff != "" {
+			return fmt.Errorf("job meta mismatch (-want +got):\n%s", diff)
+		}
+
+		tg := job.TaskGroups[0]
+		if got, want := *tg.Count, count; got != want {
+			return fmt.Errorf("count is %d; want %d", got, want)
+		}
+		if got, want := tg.Meta["tier"], tier; got != want {
+			return fmt.Errorf("group meta tier is %q; want %q", got, want)
+		}
+
+		return nil
+	}
+}
+
+func TestApplyJobOverrides(t *testing.T) {
+	newJob := func() *api.Job {
+		return &api.Job{
+			ID:          helper.StringToPtr("example"),
+			Datacenters: []string{"dc1"},
+			Priority:    helper.IntToPtr(50),
+			Meta:        map[string]string{"a": "1"},
+			TaskGroups: []*api.TaskGroup{
+				{
+					Name:  helper.StringToPtr("web"),
+					Count: helper.IntToPtr(3),
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		overrides   JobOverrides
+		expectedJob func() *api.Job
+		expectedErr string
+	}{
+		{
+			name:        "no overrides",
+			overrides:   JobOverrides{},
+			expectedJob: newJob,
+		},
+		{
+			name: "job level overrides",
+			overrides: JobOverrides{
+				Datacenters: []string{"dc2", "dc3"},
+				Priority:    80,
+				Region:      "eu",
+				Meta:        map[string]string{"b": "2"},
+			},
+			expectedJob: func() *api.Job {
+				job := newJob()
+				job.Datacenters = []string{"dc2", "dc3"}
+				job.Priority = helper.IntToPtr(80)
+				job.Region = helper.StringToPtr("eu")
+				job.Meta["b"] = "2"
+				return job
+			},
+		},
+		{
+			name: "task group overrides",
+			overrides: JobOverrides{
+				TaskGroups: []TaskGroupOverrides{
+					{
+						Name:  "web",
+						Count: helper.IntToPtr(0),
+						Meta:  map[string]string{"c": "3"},
+					},
+				},
+			},
+			expectedJob: func() *api.Job {
+				job := newJob()
+				job.TaskGroups[0].Count = helper.IntToPtr(0)
+				job.TaskGroups[0].Meta = map[string]string{"c": "3"}
+				return job
+			},
+		},
+		{
+			name: "task group not found",
+			overrides: JobOverrides{
+				TaskGroups: []TaskGroupOverrides{{Name: "api"}},
+			},
+			expectedErr: `task group "api" not found`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := newJob()
+			err := applyJobOverrides(job, tc.overrides)
+
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedJob(), job)
+		})
+	}
+}
+
+func TestAllocatedPortsRaw(t *testing.T) {
+	resources := &api.AllocatedResources{
+		Shared: api.AllocatedSharedResources{
+			Ports: []api.PortMapping{
+				{Label: "http", Value: 23456, To: 8080, HostIP: "10.0.0.1"},
+			},
+		},
+		Tasks: map[string]*api.AllocatedTaskResources{
+			"web": {
+				Networks: []*api.NetworkResource{
+					{
+						IP:            "10.0.0.1",
+						ReservedPorts: []api.Port{{Label: "admin", Value: 9000}},
+						DynamicPorts:  []api.Port{{Label: "http", Value: 23456, To: 8080}},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []interface{}{
+		map[string]interface{}{"label": "admin", "host_ip": "10.0.0.1", "value": 9000, "to": 0},
+		map[string]interface{}{"label": "http", "host_ip": "10.0.0.1", "value": 23456, "to": 8080},
+	}
+	require.Equal(t, expected, allocatedPortsRaw(resources))
+	require.Empty(t, allocatedPortsRaw(nil))
+
+	// When tasks share a label, the port of the first task by name is kept.
+	resources.Tasks["sidecar"] = &api.AllocatedTaskResources{
+		Networks: []*api.NetworkResource{
+			{IP: "10.0.0.2", DynamicPorts: []api.Port{{Label: "metrics", Value: 30001}}},
+		},
+	}
+	resources.Tasks["api"] = &api.AllocatedTaskResources{
+		Networks: []*api.NetworkResource{
+			{IP: "10.0.0.3", DynamicPorts: []api.Port{{Label: "metrics", Value: 30000}}},
+		},
+	}
+	for i := 0; i < 20; i++ {
+		ports := allocatedPortsRaw(resources)
+		require.Len(t, ports, 3)
+		require.Equal(t, 30000, ports[2].(map[string]interface{})["value"])
+	}
+}
+
+func TestResourceJob_readAllocations(t *testing.T) {
+	job := testResourceJob_unitJob(t)
+	resources := &api.AllocatedResources{
+		Shared: api.AllocatedSharedResources{
+			Ports: []api.PortMapping{{Label: "http", Value: 23456, To: 8080, HostIP: "10.0.0.1"}},
+		},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/job/example", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(job)
+	})
+	mux.HandleFunc("/v1/allocations", func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "true", req.URL.Query().Get("resources"))
+		require.Equal(t, "default", req.URL.Query().Get("namespace"))
+		json.NewEncoder(w).Encode([]*api.AllocationListStub{
+			{ID: "alloc-1", JobID: "example", ClientStatus: "running", AllocatedResources: resources},
+			{ID: "alloc-2", JobID: "example", ClientStatus: "complete", AllocatedResources: resources},
+			{ID: "alloc-3", JobID: "other", ClientStatus: "running", AllocatedResources: resources},
+		})
+	})
+	mux.HandleFunc("/v1/allocation/", func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("unexpected request to %s", req.URL.Path)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	d := resourceJob().Data(&terraform.InstanceState{
+		ID:         "example",
+		Attributes: map[string]string{"namespace": "default"},
+	})
+	require.NoError(t, resourceJobRead(d, ProviderConfig{client: client}))
+
+	require.Equal(t, []interface{}{"alloc-1", "alloc-2"}, d.Get("allocation_ids"))
+	require.Equal(t, 1, d.Get("allocations.0.ports.#"))
+	require.Equal(t, 23456, d.Get("allocations.0.ports.0.value"))
+	require.Equal(t, 0, d.Get("allocations.1.ports.#"))
+}
+
+func TestResourceJob_storeJobspecHash(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config: testResourceJob_storeJobspecHashConfig("10"),
+				Check: r.ComposeTestCheckFunc(
+					r.TestMatchResourceAttr("nomad_job.hash", "jobspec", regexp.MustCompile("^sha256:[0-9a-f]{64}$")),
+					testResourceJob_checkExists("foo-hash"),
+				),
+			},
+			{
+				Config: testResourceJob_storeJobspecHashConfig("20"),
+				Check: func(s *terraform.State) error {
+					providerConfig := testProvider.Meta().(ProviderConfig)
+					client := providerConfig.client
+					job, _, err := client.Jobs().Info("foo-hash", nil)
+					if err != nil {
+						return fmt.Errorf("error reading back job: %s", err)
+					}
+					if got, want := job.TaskGroups[0].Tasks[0].Config["args"], []interface{}{"20"}; !reflect.DeepEqual(got, want) {
+						return fmt.Errorf("task args are %v; want %v", got, want)
+					}
+					return nil
+				},
+			},
+		},
+		CheckDestroy: testResourceJob_checkDestroy("foo-hash"),
+	})
+}
+
+func testResourceJob_storeJobspecHashConfig(sleep string) string {
+	return fmt.Sprintf(`
+resource "nomad_job" "hash" {
+	store_jobspec = "hash"
+
+	jobspec = <<EOT
+		job "foo-hash" {
+			datacenters = ["dc1"]
+			type = "service"
+			group "foo" {
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/sleep"
+						args = ["%s"]
+					}
+
+					resources {
+						cpu = 100
+						memory = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+`, sleep)
+}
+
+func TestJobspecHash(t *testing.T) {
+	jobHCL := `
+job "example" {
+  datacenters = ["dc1"]
+  group "example" {
+    task "example" {
+      driver = "docker"
+      config {
+        image = "alpine"
+      }
+    }
+  }
+}
+`
+	reformattedJobHCL := `
+job "example" {
+    datacenters = [ "dc1" ]
+
+    group "example" {
+        count = 1
+        task "example" {
+            driver = "docker"
+            config { image = "alpine" }
+        }
+    }
+}
+`
+	changedJobHCL := strings.Replace(jobHCL, "alpine", "busybox", 1)
+
+	hash, err := jobspecHash(jobHCL, JobParserConfig{})
+	require.NoError(t, err)
+	require.Regexp(t, "^sha256:[0-9a-f]{64}$", hash)
+
+	reformattedHash, err := jobspecHash(reformattedJobHCL, JobParserConfig{})
+	require.NoError(t, err)
+	require.Equal(t, hash, reformattedHash)
+
+	changedHash, err := jobspecHash(changedJobHCL, JobParserConfig{})
+	require.NoError(t, err)
+	require.NotEqual(t, hash, changedHash)
+
+	d := resourceJob().Data(&terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"jobspec":       hash,
+			"store_jobspec": "hash",
+		},
+	})
+	require.True(t, jobspecDiffSuppress("jobspec", hash, reformattedJobHCL, d))
+	require.False(t, jobspecDiffSuppress("jobspec", hash, changedJobHCL, d))
+}
+
+// testResourceJob_fakeNomad starts a fake Nomad API serving job and
+// recording the jobs registered.
+func testResourceJob_fakeNomad(t *testing.T, job *api.Job) (*api.Client, *[]*api.Job, func()) {
+	var registered []*api.Job
+	var lock sync.Mutex
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/jobs", func(w http.ResponseWriter, req *http.Request) {
+		var register api.JobRegisterRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&register))
+		lock.Lock()
+		registered = append(registered, register.Job)
+		lock.Unlock()
+		json.NewEncoder(w).Encode(api.JobRegisterResponse{EvalID: "register-eval", JobModifyIndex: 6})
+	})
+	mux.HandleFunc("/v1/job/"+*job.ID+"/plan", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(api.JobPlanResponse{JobModifyIndex: *job.JobModifyIndex})
+	})
+	mux.HandleFunc("/v1/job/"+*job.ID+"/allocations", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("[]"))
+	})
+	mux.HandleFunc("/v1/job/"+*job.ID, func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(job)
+	})
+	server := httptest.NewServer(mux)
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+	return client, &registered, server.Close
+}
+
+const testResourceJob_unitJobHCL = `
+job "example" {
+  datacenters = ["dc1"]
+  group "example" {
+    task "example" {
+      driver = "docker"
+      config {
+        image = "alpine"
+      }
+    }
+  }
+}
+`
+
+func testResourceJob_unitJob(t *testing.T) *api.Job {
+	job, err := parseJobspec(testResourceJob_unitJobHCL, JobParserConfig{}, nil, nil)
+	require.NoError(t, err)
+	job.Canonicalize()
+	job.JobModifyIndex = helper.Uint64ToPtr(5)
+	return job
+}
+
+func TestResourceJob_storeJobspecHashUpdate(t *testing.T) {
+	jobHCL := testResourceJob_unitJobHCL
+	hash, err := jobspecHash(jobHCL, JobParserConfig{})
+	require.NoError(t, err)
+
+	client, registered, stop := testResourceJob_fakeNomad(t, testResourceJob_unitJob(t))
+	defer stop()
+	meta := ProviderConfig{client: client}
+
+	res := resourceJob()
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":            "example",
+			"jobspec":       hash,
+			"store_jobspec": "hash",
+			"detach":        "true",
+			"namespace":     "default",
+			"modify_index":  "5",
+		},
+	}
+
+	apply := func(config map[string]interface{}) *terraform.InstanceState {
+		diff, err := res.Diff(state, terraform.NewResourceConfigRaw(config), meta)
+		require.NoError(t, err)
+		require.NotNil(t, diff)
+
+		newState, err := res.Apply(state, diff, meta)
+		require.NoError(t, err)
+		require.Equal(t, hash, newState.Attributes["jobspec"])
+		return newState
+	}
+
+	// Changing an argument handled by the provider doesn't register the
+	// job again.
+	newState := apply(map[string]interface{}{
+		"jobspec":          jobHCL,
+		"store_jobspec":    "hash",
+		"detach":           true,
+		"purge_on_destroy": true,
+	})
+	require.Equal(t, "true", newState.Attributes["purge_on_destroy"])
+	require.Empty(t, *registered)
+
+	// Changing the overrides registers the job parsed from the jobspec.
+	apply(map[string]interface{}{
+		"jobspec":       jobHCL,
+		"store_jobspec": "hash",
+		"override": []interface{}{
+			map[string]interface{}{"priority": 70},
+		},
+	})
+	require.Len(t, *registered, 1)
+	require.Equal(t, 70, *(*registered)[0].Priority)
+}
+
+func TestResourceJob_storeJobspecTransition(t *testing.T) {
+	jobHCL := testResourceJob_unitJobHCL
+	hash, err := jobspecHash(jobHCL, JobParserConfig{})
+	require.NoError(t, err)
+
+	client, registered, stop := testResourceJob_fakeNomad(t, testResourceJob_unitJob(t))
+	defer stop()
+	meta := ProviderConfig{client: client}
+
+	res := resourceJob()
+	apply := func(state *terraform.InstanceState, storeJobspec string) *terraform.InstanceState {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"jobspec":       jobHCL,
+			"store_jobspec": storeJobspec,
+			"detach":        true,
+		})
+		diff, err := res.Diff(state, config, meta)
+		require.NoError(t, err)
+		require.NotNil(t, diff)
+
+		newState, err := res.Apply(state, diff, meta)
+		require.NoError(t, err)
+		return newState
+	}
+
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":            "example",
+			"jobspec":       hash,
+			"store_jobspec": "hash",
+			"detach":        "true",
+			"namespace":     "default",
+			"modify_index":  "5",
+		},
+	}
+
+	// Going from "hash" to "full" stores the jobspec again.
+	state = apply(state, "full")
+	require.Equal(t, "full", state.Attributes["store_jobspec"])
+	require.Equal(t, jobHCL, state.Attributes["jobspec"])
+
+	// The next plan is empty.
+	diff, err := res.Diff(state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"jobspec":       jobHCL,
+		"store_jobspec": "full",
+		"detach":        true,
+	}), meta)
+	require.NoError(t, err)
+	require.Nil(t, diff)
+
+	// Going back to "hash" stores the hash.
+	state = apply(state, "hash")
+	require.Equal(t, hash, state.Attributes["jobspec"])
+
+	// The job didn't change so it was never registered again.
+	require.Empty(t, *registered)
+}
+
+func TestResourceJob_sensitiveJobspec(t *testing.T) {
+	res := resourceJob()
+	require.True(t, res.Schema["sensitive_jobspec"].Sensitive)
+
+	// Exactly one of jobspec and sensitive_jobspec must be set.
+	_, errs := res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	require.NotEmpty(t, errs)
+	_, errs = res.Validate(terraform.NewResourceConfigRaw(map[string]interface{}{
+		"jobspec":           testResourceJob_unitJobHCL,
+		"sensitive_jobspec": testResourceJob_unitJobHCL,
+	}))
+	require.NotEmpty(t, errs)
+
+	client, registered, stop := testResourceJob_fakeNomad(t, testResourceJob_unitJob(t))
+	defer stop()
+	meta := ProviderConfig{client: client}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"sensitive_jobspec": testResourceJob_unitJobHCL,
+		"store_jobspec":     "hash",
+	})
+	_, errs = res.Validate(config)
+	require.Empty(t, errs)
+
+	diff, err := res.Diff(nil, config, meta)
+	require.NoError(t, err)
+	state, err := res.Apply(nil, diff, meta)
+	require.NoError(t, err)
+
+	hash, err := jobspecHash(testResourceJob_unitJobHCL, JobParserConfig{})
+	require.NoError(t, err)
+	require.Equal(t, hash, state.Attributes["sensitive_jobspec"])
+	require.Empty(t, state.Attributes["jobspec"])
+	require.Len(t, *registered, 1)
+	require.Equal(t, "example", *(*registered)[0].ID)
+}
+
+func TestResourceJob_stopChildrenOnDestroy(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config: testResourceJob_stopChildrenOnDestroy,
+				Check:  testResourceJob_checkExists("parameterized-children"),
+			},
+			{
+				PreConfig: func() {
+					providerConfig := testProvider.Meta().(ProviderConfig)
+					client := providerConfig.client
+					_, _, err := client.Jobs().Dispatch("parameterized-children", nil, []byte("payload"), nil)
+					if err != nil {
+						t.Fatalf("error dispatching job: %s", err)
+					}
+				},
+				Destroy: true,
+				Config:  testResourceJob_stopChildrenOnDestroy,
+				Check: func(s *terraform.State) error {
+					providerConfig := testProvider.Meta().(ProviderConfig)
+					client := providerConfig.client
+					children, _, err := client.Jobs().PrefixList("parameterized-children/")
+					if err != nil {
+						return fmt.Errorf("error listing child jobs: %s", err)
+					}
+					if len(children) == 0 {
+						return errors.New("expected dispatched child job")
+					}
+					for _, child := range children {
+						if !child.Stop {
+							return fmt.Errorf("child job %q was not stopped", child.ID)
+						}
+					}
+					return nil
+				},
+			},
+		},
+		CheckDestroy: testResourceJob_checkDestroy("parameterized-children"),
+	})
+}
+
+var testResourceJob_stopChildrenOnDestroy = `
+resource "nomad_job" "parameterized" {
+	stop_children_on_destroy = true
+
+	jobspec = <<EOT
+		job "parameterized-children" {
+			datacenters = ["dc1"]
+			type = "batch"
+			parameterized {
+				payload = "required"
+			}
+			group "foo" {
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/sleep"
+						args = ["30"]
+					}
+					resources {
+						cpu = 100
+						memory = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+`
+
+func TestDeregisterChildJobs(t *testing.T) {
+	// The IDs of periodic children end with their launch time, which may
+	// contain "404".
+	purged := "parent/periodic-1624040400"
+	failing := "parent/periodic-1624040460"
+
+	var deregistered []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/jobs", func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, "parent/", req.URL.Query().Get("prefix"))
+		json.NewEncoder(w).Encode([]*api.JobListStub{
+			{ID: purged, ParentID: "parent"},
+			{ID: failing, ParentID: "parent"},
+			{ID: "parent/other", ParentID: "other"},
+		})
+	})
+	mux.HandleFunc("/v1/job/", func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/job/" + purged + "/allocations":
+			http.Error(w, "job not found", http.StatusNotFound)
+		case "/v1/job/" + failing + "/allocations":
+			http.Error(w, "error reading job "+failing, http.StatusInternalServerError)
+		default:
+			deregistered = append(deregistered, strings.TrimPrefix(req.URL.Path, "/v1/job/"))
+			json.NewEncoder(w).Encode(api.JobDeregisterResponse{})
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	err = deregisterChildJobs(client, "parent", true, "default", time.Second)
+	require.Equal(t, []string{purged, failing}, deregistered)
+
+	// The allocations of the purged child are not found, but the error
+	// listing the ones of the other child isn't mistaken for a 404.
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `error listing allocations for child job "`+failing+`"`)
+}
+
+func TestAPIResponseStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/job/missing":
+			http.Error(w, "job not found", http.StatusNotFound)
+		default:
+			http.Error(w, "error 404", http.StatusInternalServerError)
+		}
+	}))
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	_, _, err = client.Jobs().Info("missing", nil)
+	require.Equal(t, http.StatusNotFound, apiResponseStatus(err))
+
+	_, _, err = client.Jobs().Info("404", nil)
+	require.Equal(t, http.StatusInternalServerError, apiResponseStatus(err))
+
+	server.Close()
+	_, _, err = client.Jobs().Info("missing", nil)
+	require.Equal(t, 0, apiResponseStatus(err))
+}
+
+func TestCheckJobFeasibility(t *testing.T) {
+	nodes := []*api.NodeListStub{
+		{
+			ID:                    "node-1",
+			Datacenter:            "dc1",
+			NodeClass:             "web",
+			Status:                "ready",
+			SchedulingEligibility: "eligible",
+			Drivers: map[string]*api.DriverInfo{
+				"docker":   {Detected: true, Healthy: true},
+				"raw_exec": {Detected: true, Healthy: false},
+			},
+		},
+		{
+			ID:                    "node-2",
+			Datacenter:            "dc2",
+			NodeClass:             "batch",
+			Status:                "down",
+			SchedulingEligibility: "eligible",
+			Drivers: map[string]*api.DriverInfo{
+				"raw_exec": {Detected: true, Healthy: true},
+			},
+		},
+		{
+			ID:                    "node-3",
+			Datacenter:            "dc1",
+			NodeClass:             "batch",
+			Status:                "ready",
+			SchedulingEligibility: "ineligible",
+			Drivers: map[string]*api.DriverInfo{
+				"raw_exec": {Detected: true, Healthy: true},
+			},
+		},
+		{
+			ID:                    "node-4",
+			Datacenter:            "dc3",
+			NodeClass:             "batch",
+			Status:                "ready",
+			Drain:                 true,
+			SchedulingEligibility: "eligible",
+			Drivers: map[string]*api.DriverInfo{
+				"raw_exec": {Detected: true, Healthy: true},
+			},
+		},
+	}
+	hostVolumes := map[string][]string{
+		"node-1": {"data"},
+	}
+
+	newJob := func() *api.Job {
+		return &api.Job{
+			ID:          helper.StringToPtr("example"),
+			Datacenters: []string{"dc1"},
+			TaskGroups: []*api.TaskGroup{
+				{
+					Name: helper.StringToPtr("web"),
+					Constraints: []*api.Constraint{
+						{LTarget: "${node.class}", RTarget: "web", Operand: "="},
+					},
+					Volumes: map[string]*api.VolumeRequest{
+						"data": {Name: "data", Type: "host", Source: "data"},
+					},
+					Tasks: []*api.Task{
+						{Name: "web", Driver: "docker"},
+					},
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		modify      func(*api.Job)
+		expectedErr []string
+	}{
+		{
+			name:   "feasible",
+			modify: func(*api.Job) {},
+		},
+		{
+			name: "datacenter without ready nodes",
+			modify: func(job *api.Job) {
+				job.Datacenters = []string{"dc1", "dc2", "us-east-1a"}
+			},
+			expectedErr: []string{
+				`datacenter "dc2" has no ready nodes`,
+				`datacenter "us-east-1a" has no ready nodes`,
+			},
+		},
+		{
+			name: "unhealthy driver",
+			modify: func(job *api.Job) {
+				job.TaskGroups[0].Tasks = append(job.TaskGroups[0].Tasks,
+					&api.Task{Name: "a", Driver: "raw_exec"},
+					&api.Task{Name: "b", Driver: "raw_exec"},
+				)
+			},
+			expectedErr: []string{`driver "raw_exec" is not healthy on any ready node`},
+		},
+		{
+			name: "missing node class",
+			modify: func(job *api.Job) {
+				job.Constraints = []*api.Constraint{
+					{LTarget: "${node.class}", RTarget: "batch", Operand: "="},
+				}
+			},
+			expectedErr: []string{`node class "batch" is not available on any ready node`},
+		},
+		{
+			name: "node class with another operator",
+			modify: func(job *api.Job) {
+				job.Constraints = []*api.Constraint{
+					{LTarget: "${node.class}", RTarget: "web", Operand: "!="},
+					{LTarget: "${node.class}", RTarget: "^b", Operand: "regexp"},
+				}
+			},
+		},
+		{
+			name: "draining and ineligible nodes",
+			modify: func(job *api.Job) {
+				job.Datacenters = []string{"dc1", "dc3"}
+				job.TaskGroups[0].Tasks[0].Driver = "raw_exec"
+			},
+			expectedErr: []string{
+				`datacenter "dc3" has no ready nodes`,
+				`driver "raw_exec" is not healthy on any ready node`,
+			},
+		},
+		{
+			name: "missing host volume",
+			modify: func(job *api.Job) {
+				job.TaskGroups[0].Volumes["logs"] = &api.VolumeRequest{Name: "logs", Type: "host", Source: "logs"}
+			},
+			expectedErr: []string{`host volume "logs" is not offered by any ready node`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := newJob()
+			tc.modify(job)
+
+			err := checkJobFeasibility(job, nodes, hostVolumes)
+			if len(tc.expectedErr) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, e := range tc.expectedErr {
+				require.Contains(t, err.Error(), e)
+			}
+			require.Equal(t, len(tc.expectedErr), strings.Count(err.Error(), "* "))
+		})
+	}
+}
+
+func TestResourceJob_feasibilityChecks(t *testing.T) {
+	r.Test(t, r.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []r.TestStep{
+			{
+				Config:      testResourceJob_feasibilityChecksConfig("us-east-1a"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`datacenter "us-east-1a" has no ready nodes`),
+			},
+			{
+				Config: testResourceJob_feasibilityChecksConfig("dc1"),
+				Check:  testResourceJob_checkExists("foo-feasibility"),
+			},
+		},
+		CheckDestroy: testResourceJob_checkDestroy("foo-feasibility"),
+	})
+}
+
+func testResourceJob_feasibilityChecksConfig(dc string) string {
+	return fmt.Sprintf(`
+resource "nomad_job" "feasibility" {
+	feasibility_checks = true
+
+	jobspec = <<EOT
+		job "foo-feasibility" {
+			datacenters = ["%s"]
+			type = "service"
+			group "foo" {
+				task "foo" {
+					driver = "raw_exec"
+					config {
+						command = "/bin/sleep"
+						args = ["10"]
+					}
+
+					resources {
+						cpu = 100
+						memory = 10
+					}
+				}
+			}
+		}
+	EOT
+}
+`, dc)
+}
+
+func TestResourceJob_postDeployCheckRollback(t *testing.T) {
+	oldHCL := testResourceJob_unitJobHCL
+	newHCL := strings.Replace(oldHCL, "alpine", "busybox", 1)
+	job := testResourceJob_unitJob(t)
+
+	// The deployment has no allocation so the check fails, and version 0 is
+	// the stable version to roll back to.
+	var lock sync.Mutex
+	registered := 0
+	var revertedTo []uint64
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/jobs", func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		registered++
+		lock.Unlock()
+		json.NewEncoder(w).Encode(api.JobRegisterResponse{EvalID: "register-eval", JobModifyIndex: 6})
+	})
+	mux.HandleFunc("/v1/job/example/plan", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(api.JobPlanResponse{JobModifyIndex: *job.JobModifyIndex})
+	})
+	mux.HandleFunc("/v1/job/example", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(job)
+	})
+	mux.HandleFunc("/v1/evaluation/register-eval", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(api.Evaluation{ID: "register-eval", Status: "complete", DeploymentID: "deploy-1"})
+	})
+	mux.HandleFunc("/v1/deployment/deploy-1", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(api.Deployment{ID: "deploy-1", Status: "successful"})
+	})
+	mux.HandleFunc("/v1/deployment/allocations/deploy-1", func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("[]"))
+	})
+	mux.HandleFunc("/v1/job/example/versions", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(api.JobVersionsResponse{
+			Versions: []*api.Job{
+				{ID: helper.StringToPtr("example"), Version: helper.Uint64ToPtr(1), Stable: helper.BoolToPtr(false)},
+				{ID: helper.StringToPtr("example"), Version: helper.Uint64ToPtr(0), Stable: helper.BoolToPtr(true)},
+			},
+		})
+	})
+	mux.HandleFunc("/v1/job/example/revert", func(w http.ResponseWriter, req *http.Request) {
+		var revert api.JobRevertRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&revert))
+		lock.Lock()
+		revertedTo = append(revertedTo, revert.JobVersion)
+		lock.Unlock()
+		json.NewEncoder(w).Encode(api.JobRegisterResponse{JobModifyIndex: 7})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+	meta := ProviderConfig{client: client}
+
+	res := resourceJob()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"jobspec": newHCL,
+		"detach":  false,
+		"post_deploy_check": []interface{}{
+			map[string]interface{}{
+				"task_group": "example",
+				"port_label": "http",
+				"rollback":   true,
+			},
+		},
+	})
+
+	// On update, the previous jobspec is kept so the next plan registers the
+	// new one again.
+	state := &terraform.InstanceState{
+		ID: "example",
+		Attributes: map[string]string{
+			"id":           "example",
+			"jobspec":      oldHCL,
+			"detach":       "false",
+			"namespace":    "default",
+			"modify_index": "5",
+		},
+	}
+	diff, err := res.Diff(state, config, meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+
+	newState, err := res.Apply(state, diff, meta)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "was rolled back to version 0")
+	require.Equal(t, 1, registered)
+	require.Equal(t, []uint64{0}, revertedTo)
+	require.Equal(t, oldHCL, newState.Attributes["jobspec"])
+
+	diff, err = res.Diff(newState, config, meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+	require.Contains(t, diff.Attributes, "jobspec")
+
+	// On create, the job rolled back to a version registered outside of
+	// Terraform is not added to the state.
+	diff, err = res.Diff(nil, config, meta)
+	require.NoError(t, err)
+
+	newState, err = res.Apply(nil, diff, meta)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "was rolled back to version 0")
+	require.Equal(t, []uint64{0, 0}, revertedTo)
+	require.True(t, newState == nil || newState.ID == "")
+}
+
+func TestResourceJobPostDeployChecks_staleAllocations(t *testing.T) {
+	// The allocation of the previous deployment is healthy and passes the
+	// check, while the one of the new deployment never became healthy.
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+	defer app.Close()
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(app.URL, "http://"))
+	require.NoError(t, err)
+	portValue, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	oldAlloc := &api.AllocationListStub{
+		ID:               "old-alloc",
+		TaskGroup:        "web",
+		ClientStatus:     "running",
+		DesiredStatus:    "run",
+		DeploymentStatus: &api.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)},
+	}
+	newAlloc := &api.AllocationListStub{
+		ID:               "new-alloc",
+		TaskGroup:        "web",
+		ClientStatus:     "running",
+		DesiredStatus:    "run",
+		DeploymentStatus: &api.AllocDeploymentStatus{Healthy: helper.BoolToPtr(false)},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/job/example/allocations", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode([]*api.AllocationListStub{oldAlloc, newAlloc})
+	})
+	mux.HandleFunc("/v1/deployment/allocations/deploy-2", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode([]*api.AllocationListStub{newAlloc})
+	})
+	mux.HandleFunc("/v1/allocation/old-alloc", func(w http.ResponseWriter, req *http.Request) {
+		json.NewEncoder(w).Encode(&api.Allocation{
+			ID: "old-alloc",
+			AllocatedResources: &api.AllocatedResources{
+				Shared: api.AllocatedSharedResources{
+					Ports: []api.PortMapping{{Label: "http", Value: portValue, HostIP: host}},
+				},
+			},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	checks := []PostDeployCheck{{TaskGroup: "web", PortLabel: "http", Path: "/", ExpectedStatus: 200}}
+	rolledBack, err := resourceJobPostDeployChecks(context.Background(), client, "example", "default", &api.Deployment{ID: "deploy-2"}, checks)
+	require.False(t, rolledBack)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `no healthy allocation found for task group "web"`)
+}
+
+func TestProbePostDeployCheck_interrupted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	// The retries are not waited for once ctx is cancelled.
+	check := PostDeployCheck{Path: "/", ExpectedStatus: 200, Retries: 5, RetryInterval: time.Hour}
+	start := time.Now()
+	err := probePostDeployCheck(ctx, http.DefaultClient, address, check)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "interrupted before attempt 2 of 6")
+	require.Less(t, int64(time.Since(start)), int64(10*time.Second))
+}
+
+func TestProbePostDeployCheck(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		switch req.URL.Path {
+		case "/flaky":
+			// Fail the first request to exercise the retries.
+			if requests == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`{"status": "ok"}`))
+		case "/health":
+			w.Write([]byte(`{"status": "ok"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	testCases := []struct {
+		name     string
+		check    PostDeployCheck
+		requests int
+		err      string
+	}{
+		{
+			name:     "retry until success",
+			check:    PostDeployCheck{Path: "/flaky", ExpectedStatus: 200, Retries: 2},
+			requests: 2,
+		},
+		{
+			name: "body matches",
+			check: PostDeployCheck{
+				Path:           "/health",
+				ExpectedStatus: 200,
+				BodyRegex:      regexp.MustCompile(`"status": "ok"`),
+			},
+			requests: 1,
+		},
+		{
+			name: "body doesn't match",
+			check: PostDeployCheck{
+				Path:           "/health",
+				ExpectedStatus: 200,
+				BodyRegex:      regexp.MustCompile(`"status": "degraded"`),
+			},
+			requests: 1,
+			err:      "doesn't match",
+		},
+		{
+			name:     "expected status",
+			check:    PostDeployCheck{Path: "/missing", ExpectedStatus: 404},
+			requests: 1,
+		},
+		{
+			name:     "retries exhausted",
+			check:    PostDeployCheck{Path: "/missing", ExpectedStatus: 200, Retries: 1},
+			requests: 2,
+			err:      "returned status 404, expected 200",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = 0
+			err := probePostDeployCheck(context.Background(), http.DefaultClient, address, tc.check)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.err)
+			}
+			require.Equal(t, tc.requests, requests)
+		})
+	}
+}
+
+func TestAllocHealthy(t *testing.T) {
+	deployment := &api.Deployment{ID: "deploy-1"}
+
+	testCases := []struct {
+		name       string
+		alloc      *api.AllocationListStub
+		deployment *api.Deployment
+		expected   bool
+	}{
+		{
+			name:       "healthy",
+			alloc:      &api.AllocationListStub{ClientStatus: "running", DesiredStatus: "run", DeploymentStatus: &api.AllocDeploymentStatus{Healthy: helper.BoolToPtr(true)}},
+			deployment: deployment,
+			expected:   true,
+		},
+		{
+			name:       "unhealthy",
+			alloc:      &api.AllocationListStub{ClientStatus: "running", DesiredStatus: "run", DeploymentStatus: &api.AllocDeploymentStatus{Healthy: helper.BoolToPtr(false)}},
+			deployment: deployment,
+		},
+		{
+			name:       "health not set",
+			alloc:      &api.AllocationListStub{ClientStatus: "running", DesiredStatus: "run"},
+			deployment: deployment,
+		},
+		{
+			name:     "running without deployment",
+			alloc:    &api.AllocationListStub{ClientStatus: "running", DesiredStatus: "run"},
+			expected: true,
+		},
+		{
+			name:  "stopping",
+			alloc: &api.AllocationListStub{ClientStatus: "running", DesiredStatus: "stop"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, allocHealthy(tc.alloc, tc.deployment))
+		})
+	}
+}
+
+func TestAllocPortAddress(t *testing.T) {
+	resources := &api.AllocatedResources{
+		Shared: api.AllocatedSharedResources{
+			Ports: []api.PortMapping{
+				{Label: "http", Value: 23456, To: 8080, HostIP: "10.0.0.1"},
+			},
+		},
+	}
+
+	address, ok := allocPortAddress(resources, "http")
+	require.True(t, ok)
+	require.Equal(t, "10.0.0.1:23456", address)
+
+	_, ok = allocPortAddress(resources, "admin")
+	require.False(t, ok)
+}
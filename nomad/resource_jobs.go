@@ -0,0 +1,519 @@
+package nomad
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceJobs() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceJobsWrite,
+		Update:        resourceJobsWrite,
+		Delete:        resourceJobsDelete,
+		Read:          resourceJobsRead,
+		CustomizeDiff: resourceJobsCustomizeDiff,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"glob": {
+				Description:  "Pattern of the jobspec files to register.",
+				Required:     true,
+				Type:         schema.TypeString,
+				ValidateFunc: validateJobsGlob,
+			},
+
+			"json": {
+				Description: "If true, the jobspec files will be parsed as json instead of HCL.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"hcl2": {
+				Description: "Configuration for the HCL2 jobspec parser.",
+				Optional:    true,
+				Type:        schema.TypeList,
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"enabled": {
+							Description: "If true, the jobspec files will be parsed as HCL2 instead of HCL.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"allow_fs": {
+							Description: "If true, HCL2 file system functions will be enabled when parsing the jobspec files.",
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+						},
+						"vars": {
+							Description: "Additional variables to use when templating the jobs with HCL2",
+							Type:        schema.TypeMap,
+							Optional:    true,
+						},
+					},
+				},
+			},
+
+			"concurrency": {
+				Description:  "Maximum number of jobs registered or monitored at the same time.",
+				Optional:     true,
+				Default:      4,
+				Type:         schema.TypeInt,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"detach": {
+				Description: "If true, the provider will return immediately after registering the jobs instead of monitoring their deployments.",
+				Optional:    true,
+				Default:     true,
+				Type:        schema.TypeBool,
+			},
+
+			"purge_on_destroy": {
+				Description: "Whether to purge the jobs when they are deregistered.",
+				Optional:    true,
+				Type:        schema.TypeBool,
+			},
+
+			"files": {
+				Description: "SHA-256 hash of the content of each jobspec file.",
+				Computed:    true,
+				Type:        schema.TypeMap,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+
+			"policy_warnings": policyWarningsSchema(),
+
+			"jobs": {
+				Description: "The jobs registered from the jobspec files.",
+				Computed:    true,
+				Type:        schema.TypeList,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"namespace": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"file": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"modify_index": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"deployment_id": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+						"deployment_status": {
+							Computed: true,
+							Type:     schema.TypeString,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// validateJobsGlob rejects the patterns filepath.Glob would match
+// differently than expected: it has no `**` to match any number of
+// directories, and treats it as `*`.
+func validateJobsGlob(v interface{}, k string) ([]string, []error) {
+	pattern := v.(string)
+	if strings.Contains(pattern, "**") {
+		return nil, []error{fmt.Errorf("%s: %q can't contain `**`, each `*` only matches within a single directory", k, pattern)}
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, []error{fmt.Errorf("%s: invalid glob %q: %s", k, pattern, err)}
+	}
+	return nil, nil
+}
+
+// jobsFileHashes returns the SHA-256 hash of the content of each file
+// matching the pattern.
+func jobsFileHashes(pattern string) (map[string]string, error) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %s", pattern, err)
+	}
+
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading jobspec file %q: %s", file, err)
+		}
+		sum := sha256.Sum256(content)
+		hashes[file] = hex.EncodeToString(sum[:])
+	}
+
+	return hashes, nil
+}
+
+// parseJobsFiles parses the jobspec files and checks the jobs against the
+// provider job_policy. The violations of its `warn` rules are returned
+// prefixed by their file.
+func parseJobsFiles(files []string, config JobParserConfig, providerConfig ProviderConfig) ([]*api.Job, []string, error) {
+	jobs := make([]*api.Job, len(files))
+	warnings := make([]string, 0)
+	seen := make(map[string]string)
+	for i, file := range files {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading jobspec file %q: %s", file, err)
+		}
+
+		job, err := parseJobspec(string(content), config, providerConfig.vaultToken, providerConfig.consulToken)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing jobspec file %q: %s", file, err)
+		}
+		if job.Namespace == nil || *job.Namespace == "" {
+			defaultNamespace := "default"
+			job.Namespace = &defaultNamespace
+		}
+		jobWarnings, err := providerConfig.jobPolicy.check(job)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error checking jobspec file %q: %s", file, err)
+		}
+		for _, w := range jobWarnings {
+			warnings = append(warnings, fmt.Sprintf("%s: %s", file, w))
+		}
+
+		key := *job.Namespace + "/" + *job.ID
+		if other, ok := seen[key]; ok {
+			return nil, nil, fmt.Errorf("job %q in namespace %q is defined in both %q and %q", *job.ID, *job.Namespace, other, file)
+		}
+		seen[key] = file
+		jobs[i] = job
+	}
+
+	return jobs, warnings, nil
+}
+
+// forEachConcurrently calls f for each index in [0, n) with at most
+// concurrency calls running at the same time, and returns all the errors.
+func forEachConcurrently(n, concurrency int, f func(i int) error) error {
+	var (
+		wg   sync.WaitGroup
+		lock sync.Mutex
+		mErr multierror.Error
+	)
+
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := f(i); err != nil {
+				lock.Lock()
+				multierror.Append(&mErr, err)
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return mErr.ErrorOrNil()
+}
+
+func resourceJobsWrite(d *schema.ResourceData, meta interface{}) error {
+	timeout := d.Timeout(schema.TimeoutCreate)
+	if !d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutUpdate)
+	}
+	// The timeout covers all the jobs, not each of them.
+	deadline := time.Now().Add(timeout)
+
+	providerConfig := meta.(ProviderConfig)
+	client := providerConfig.client
+
+	pattern := d.Get("glob").(string)
+	hashes, err := jobsFileHashes(pattern)
+	if err != nil {
+		return err
+	}
+
+	jobParserConfig, err := parseJobParserConfig(d)
+	if err != nil {
+		return err
+	}
+
+	// Parse all the jobspecs before registering any job.
+	files := make([]string, 0, len(hashes))
+	for file := range hashes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	jobs, policyWarnings, err := parseJobsFiles(files, jobParserConfig, providerConfig)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(jobs))
+	for _, job := range jobs {
+		seen[*job.Namespace+"/"+*job.ID] = struct{}{}
+	}
+
+	// The ID doesn't depend on the glob so changing it only registers and
+	// deregisters the jobs of the files that were added or removed.
+	if d.IsNewResource() {
+		d.SetId(resource.UniqueId())
+	}
+
+	// Each element is only written by the goroutine handling the job.
+	results := make([]map[string]interface{}, len(jobs))
+	concurrency := d.Get("concurrency").(int)
+
+	// Only the jobs whose file is new or changed are registered, the others
+	// are carried over from the state. Changing the parser configuration
+	// may change any job, so they are all registered again.
+	oldFiles, _ := d.GetChange("files")
+	oldJobs, _ := d.GetChange("jobs")
+	oldJobsByFile := make(map[string]map[string]interface{})
+	for _, raw := range oldJobs.([]interface{}) {
+		old := raw.(map[string]interface{})
+		oldJobsByFile[old["file"].(string)] = old
+	}
+	registerAll := d.HasChange("json") || d.HasChange("hcl2")
+
+	var changed []int
+	for i, file := range files {
+		old, ok := oldJobsByFile[file]
+		if ok && !registerAll && oldFiles.(map[string]interface{})[file] == hashes[file] {
+			results[i] = old
+			continue
+		}
+		changed = append(changed, i)
+	}
+	log.Printf("[DEBUG] registering %d of %d jobs", len(changed), len(jobs))
+
+	err = forEachConcurrently(len(changed), concurrency, func(c int) error {
+		i := changed[c]
+		job := jobs[i]
+		resp, _, err := client.Jobs().Register(job, nil)
+		if err != nil {
+			return fmt.Errorf("error registering job %q from %q: %s", *job.ID, files[i], err)
+		}
+		log.Printf("[DEBUG] job '%s' registered in namespace '%s'", *job.ID, *job.Namespace)
+
+		results[i] = map[string]interface{}{
+			"id":           *job.ID,
+			"namespace":    *job.Namespace,
+			"file":         files[i],
+			"modify_index": strconv.FormatUint(resp.JobModifyIndex, 10),
+			"eval_id":      resp.EvalID,
+		}
+		return nil
+	})
+
+	if err == nil && !d.Get("detach").(bool) {
+		ctx := providerConfig.stopCtx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		err = forEachConcurrently(len(changed), concurrency, func(c int) error {
+			result := results[changed[c]]
+			evalID := result["eval_id"].(string)
+			if evalID == "" {
+				return nil
+			}
+
+			deployment, err := monitorDeployment(ctx, client, providerConfig.eventMonitor, time.Until(deadline), result["namespace"].(string), evalID)
+			if deployment != nil {
+				result["deployment_id"] = deployment.ID
+				result["deployment_status"] = deployment.Status
+			}
+			if err != nil {
+				return fmt.Errorf("error waiting for job '%s' to schedule/deploy successfully: %s", result["id"], err)
+			}
+			return nil
+		})
+	}
+
+	// Deregister the jobs whose file was removed, or that were moved to
+	// another file under a different ID.
+	var mErr multierror.Error
+	if err != nil {
+		multierror.Append(&mErr, err)
+	}
+
+	for _, raw := range oldJobs.([]interface{}) {
+		old := raw.(map[string]interface{})
+		id, namespace := old["id"].(string), old["namespace"].(string)
+		if _, ok := seen[namespace+"/"+id]; ok {
+			continue
+		}
+
+		log.Printf("[DEBUG] deregistering job %q since its jobspec was removed", id)
+		if err := deregisterJob(client, id, namespace, d.Get("purge_on_destroy").(bool)); err != nil {
+			multierror.Append(&mErr, err)
+			// Keep the job in the state so deregistering it is retried.
+			results = append(results, old)
+		}
+	}
+
+	// Record the jobs that were registered, even if some failed.
+	jobsRaw := make([]interface{}, 0, len(results))
+	registered := make(map[string]string, len(hashes))
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		delete(result, "eval_id")
+		jobsRaw = append(jobsRaw, result)
+		if i < len(files) {
+			registered[files[i]] = hashes[files[i]]
+		}
+	}
+	d.Set("jobs", jobsRaw)
+	d.Set("files", registered)
+	d.Set("policy_warnings", policyWarnings)
+
+	return mErr.ErrorOrNil()
+}
+
+func resourceJobsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+
+	jobsRaw := d.Get("jobs").([]interface{})
+	files := d.Get("files").(map[string]interface{})
+
+	jobs := make([]interface{}, 0, len(jobsRaw))
+	for _, raw := range jobsRaw {
+		j := raw.(map[string]interface{})
+		id := j["id"].(string)
+
+		job, _, err := client.Jobs().Info(id, &api.QueryOptions{Namespace: j["namespace"].(string)})
+		if err != nil {
+			if strings.Contains(err.Error(), "404") {
+				// Forget the file so the job is registered again.
+				log.Printf("[DEBUG] job %q does not exist, so removing", id)
+				delete(files, j["file"].(string))
+				continue
+			}
+			return fmt.Errorf("error checking for job %q: %s", id, err)
+		}
+
+		// Forget the file of jobs changed outside of Terraform so they are
+		// registered again.
+		if job.JobModifyIndex != nil {
+			modifyIndex := strconv.FormatUint(*job.JobModifyIndex, 10)
+			if modifyIndex != j["modify_index"].(string) {
+				log.Printf("[DEBUG] job %q was modified, so will register it again", id)
+				delete(files, j["file"].(string))
+			}
+			j["modify_index"] = modifyIndex
+		}
+		jobs = append(jobs, j)
+	}
+
+	d.Set("jobs", jobs)
+	d.Set("files", files)
+
+	return nil
+}
+
+func resourceJobsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(ProviderConfig).client
+	purge := d.Get("purge_on_destroy").(bool)
+
+	var mErr multierror.Error
+	for _, raw := range d.Get("jobs").([]interface{}) {
+		j := raw.(map[string]interface{})
+		id := j["id"].(string)
+
+		log.Printf("[DEBUG] deregistering job: %q", id)
+		if err := deregisterJob(client, id, j["namespace"].(string), purge); err != nil {
+			multierror.Append(&mErr, err)
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+func deregisterJob(client *api.Client, id, namespace string, purge bool) error {
+	_, _, err := client.Jobs().Deregister(id, purge, &api.WriteOptions{Namespace: namespace})
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		return fmt.Errorf("error deregistering job %q: %s", id, err)
+	}
+	return nil
+}
+
+func resourceJobsCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("glob") || !d.NewValueKnown("hcl2") {
+		d.SetNewComputed("files")
+		d.SetNewComputed("jobs")
+		d.SetNewComputed("policy_warnings")
+		return nil
+	}
+
+	hashes, err := jobsFileHashes(d.Get("glob").(string))
+	if err != nil {
+		return err
+	}
+
+	oldFiles := make(map[string]string)
+	for k, v := range d.Get("files").(map[string]interface{}) {
+		oldFiles[k] = v.(string)
+	}
+	if reflect.DeepEqual(oldFiles, hashes) && !d.HasChange("json") && !d.HasChange("hcl2") {
+		return nil
+	}
+
+	// Check the jobs during plan so a violation of the provider job_policy
+	// doesn't only fail the apply.
+	jobParserConfig, err := parseJobParserConfig(d)
+	if err != nil {
+		return err
+	}
+	files := make([]string, 0, len(hashes))
+	for file := range hashes {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	_, policyWarnings, err := parseJobsFiles(files, jobParserConfig, meta.(ProviderConfig))
+	if err != nil {
+		return err
+	}
+	if err := d.SetNew("policy_warnings", policyWarnings); err != nil {
+		return err
+	}
+
+	if err := d.SetNew("files", hashes); err != nil {
+		return err
+	}
+	return d.SetNewComputed("jobs")
+}
@@ -0,0 +1,302 @@
+package nomad
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceJobs_basic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-nomad-jobs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeJobspec := func(name string) {
+		path := filepath.Join(dir, name+".nomad")
+		require.NoError(t, ioutil.WriteFile(path, []byte(testResourceJobs_jobspec(name)), 0644))
+	}
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					writeJobspec("tf-jobs-foo")
+					writeJobspec("tf-jobs-bar")
+				},
+				Config: testResourceJobs_config(dir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nomad_jobs.test", "jobs.#", "2"),
+					resource.TestCheckResourceAttr("nomad_jobs.test", "files.%", "2"),
+					resource.TestCheckResourceAttr("nomad_jobs.test", "jobs.0.id", "tf-jobs-bar"),
+					resource.TestCheckResourceAttr("nomad_jobs.test", "jobs.0.deployment_status", "successful"),
+					resource.TestCheckResourceAttr("nomad_jobs.test", "jobs.1.id", "tf-jobs-foo"),
+					resource.TestCheckResourceAttr("nomad_jobs.test", "jobs.1.deployment_status", "successful"),
+					testResourceJobs_checkJob("tf-jobs-foo", true),
+					testResourceJobs_checkJob("tf-jobs-bar", true),
+				),
+			},
+			{
+				// Removing a file deregisters its job.
+				PreConfig: func() {
+					require.NoError(t, os.Remove(filepath.Join(dir, "tf-jobs-bar.nomad")))
+				},
+				Config: testResourceJobs_config(dir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("nomad_jobs.test", "jobs.#", "1"),
+					resource.TestCheckResourceAttr("nomad_jobs.test", "jobs.0.id", "tf-jobs-foo"),
+					testResourceJobs_checkJob("tf-jobs-foo", true),
+					testResourceJobs_checkJob("tf-jobs-bar", false),
+				),
+			},
+		},
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testResourceJobs_checkJob("tf-jobs-foo", false),
+			testResourceJobs_checkJob("tf-jobs-bar", false),
+		),
+	})
+}
+
+func testResourceJobs_config(dir string) string {
+	return fmt.Sprintf(`
+resource "nomad_jobs" "test" {
+  glob             = "%s/*.nomad"
+  detach           = false
+  purge_on_destroy = true
+}
+`, dir)
+}
+
+func testResourceJobs_jobspec(name string) string {
+	return fmt.Sprintf(`
+job %q {
+  datacenters = ["dc1"]
+
+  group "foo" {
+    task "foo" {
+      driver = "raw_exec"
+
+      config {
+        command = "/bin/sleep"
+        args    = ["3600"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 32
+      }
+    }
+  }
+}
+`, name)
+}
+
+func testResourceJobs_checkJob(id string, exists bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testProvider.Meta().(ProviderConfig).client
+		job, _, err := client.Jobs().Info(id, nil)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			return err
+		}
+
+		found := err == nil && job.Status != nil && *job.Status != "dead"
+		if found != exists {
+			return fmt.Errorf("job %q running is %t, expected %t", id, found, exists)
+		}
+		return nil
+	}
+}
+
+// testResourceJobs_fakeNomad starts a Nomad API that records the IDs of the
+// jobs registered and deregistered, in the order of the requests.
+func testResourceJobs_fakeNomad(t *testing.T) (*api.Client, func() []string, func()) {
+	var (
+		lock     sync.Mutex
+		requests []string
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/jobs", func(w http.ResponseWriter, req *http.Request) {
+		var register api.JobRegisterRequest
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&register))
+		lock.Lock()
+		requests = append(requests, "register "+*register.Job.ID)
+		lock.Unlock()
+		json.NewEncoder(w).Encode(api.JobRegisterResponse{JobModifyIndex: 6})
+	})
+	mux.HandleFunc("/v1/job/", func(w http.ResponseWriter, req *http.Request) {
+		require.Equal(t, http.MethodDelete, req.Method)
+		lock.Lock()
+		requests = append(requests, "deregister "+strings.TrimPrefix(req.URL.Path, "/v1/job/"))
+		lock.Unlock()
+		json.NewEncoder(w).Encode(api.JobDeregisterResponse{})
+	})
+	server := httptest.NewServer(mux)
+
+	client, err := api.NewClient(&api.Config{Address: server.URL})
+	require.NoError(t, err)
+
+	// Registrations run concurrently so the requests are sorted.
+	drain := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		ret := requests
+		requests = nil
+		sort.Strings(ret)
+		return ret
+	}
+	return client, drain, server.Close
+}
+
+func testResourceJobs_apply(t *testing.T, meta ProviderConfig, state *terraform.InstanceState, glob string) *terraform.InstanceState {
+	res := resourceJobs()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"glob": glob,
+	})
+	diff, err := res.Diff(state, config, meta)
+	require.NoError(t, err)
+	require.NotNil(t, diff)
+
+	state, err = res.Apply(state, diff, meta)
+	require.NoError(t, err)
+	return state
+}
+
+func TestResourceJobs_registerChangedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-nomad-jobs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	writeJobspec := func(file, name string) {
+		content := testResourceJobs_jobspec(name)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0644))
+	}
+	writeJobspec("a.nomad", "a")
+	writeJobspec("b.nomad", "b")
+	writeJobspec("c.nomad", "c")
+
+	client, requests, stop := testResourceJobs_fakeNomad(t)
+	defer stop()
+	meta := ProviderConfig{client: client}
+	glob := filepath.Join(dir, "*.nomad")
+
+	state := testResourceJobs_apply(t, meta, nil, glob)
+	require.Equal(t, []string{"register a", "register b", "register c"}, requests())
+	require.Equal(t, "3", state.Attributes["jobs.#"])
+
+	// Only the job whose file changed is registered again, and the job it
+	// used to define is deregistered.
+	writeJobspec("b.nomad", "b2")
+	writeJobspec("d.nomad", "d")
+	state = testResourceJobs_apply(t, meta, state, glob)
+	require.Equal(t, []string{"deregister b", "register b2", "register d"}, requests())
+	require.Equal(t, "4", state.Attributes["jobs.#"])
+	require.Equal(t, "a", state.Attributes["jobs.0.id"])
+	require.Equal(t, "b2", state.Attributes["jobs.1.id"])
+	require.Equal(t, "c", state.Attributes["jobs.2.id"])
+	require.Equal(t, "d", state.Attributes["jobs.3.id"])
+}
+
+func TestResourceJobs_globChange(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-nomad-jobs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.nomad", "b.nomad", "c.hcl"} {
+		content := testResourceJobs_jobspec(strings.TrimSuffix(name, filepath.Ext(name)))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	client, requests, stop := testResourceJobs_fakeNomad(t)
+	defer stop()
+	meta := ProviderConfig{client: client}
+
+	state := testResourceJobs_apply(t, meta, nil, filepath.Join(dir, "*.nomad"))
+	require.Equal(t, []string{"register a", "register b"}, requests())
+	id := state.ID
+
+	// The resource isn't replaced: the job of the file that is still
+	// matched is left alone, the other one is deregistered and the job of
+	// the new file is registered.
+	state = testResourceJobs_apply(t, meta, state, filepath.Join(dir, "[ac].*"))
+	require.Equal(t, []string{"deregister b", "register c"}, requests())
+	require.Equal(t, id, state.ID)
+	require.Equal(t, "2", state.Attributes["jobs.#"])
+	require.Equal(t, "a", state.Attributes["jobs.0.id"])
+	require.Equal(t, "c", state.Attributes["jobs.1.id"])
+}
+
+func TestValidateJobsGlob(t *testing.T) {
+	for pattern, valid := range map[string]bool{
+		"jobs/*.nomad":    true,
+		"jobs/*/*.nomad":  true,
+		"jobs/**/*.nomad": false,
+		"jobs/[.nomad":    false,
+	} {
+		_, errs := validateJobsGlob(pattern, "glob")
+		require.Equal(t, valid, len(errs) == 0, pattern)
+	}
+}
+
+func TestJobsFileHashes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf-nomad-jobs")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.nomad"), []byte("a"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.nomad"), []byte("b"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "c.txt"), []byte("c"), 0644))
+
+	hashes, err := jobsFileHashes(filepath.Join(dir, "*.nomad"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		filepath.Join(dir, "a.nomad"): "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb",
+		filepath.Join(dir, "b.nomad"): "3e23e8160039594a33894f6564e1b1348bbd7a0088d42c4acb73eeaed59c009d",
+	}, hashes)
+
+	_, err = jobsFileHashes("[")
+	require.Error(t, err)
+}
+
+func TestForEachConcurrently(t *testing.T) {
+	var running, maxRunning, calls int32
+
+	err := forEachConcurrently(20, 3, func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		atomic.AddInt32(&calls, 1)
+
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+
+		if i%5 == 0 {
+			return fmt.Errorf("error %d", i)
+		}
+		return nil
+	})
+
+	require.Equal(t, int32(20), calls)
+	require.LessOrEqual(t, maxRunning, int32(3))
+	require.Error(t, err)
+	for _, i := range []int{0, 5, 10, 15} {
+		require.Contains(t, err.Error(), fmt.Sprintf("error %d", i))
+	}
+}
@@ -0,0 +1,36 @@
+package nomad
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// computedString, computedInt, computedBool and computedMap return the schema
+// of attributes that are only read from Nomad, as used by the data sources.
+func computedString() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeString,
+	}
+}
+
+func computedInt() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeInt,
+	}
+}
+
+func computedBool() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeBool,
+	}
+}
+
+func computedMap() *schema.Schema {
+	return &schema.Schema{
+		Computed: true,
+		Type:     schema.TypeMap,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}